@@ -0,0 +1,342 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gocrud/app/core"
+)
+
+// App 是一个可编程控制的应用句柄
+// 与阻塞式的 Run 不同，App 允许调用方自行控制启动、停止与等待，
+// 适用于集成测试或将框架嵌入到其他程序中。
+type App struct {
+	rt       *core.Runtime
+	settings *settings
+
+	mu      sync.Mutex
+	started bool
+
+	stopOnce sync.Once
+	stopErr  error
+	done     chan struct{}
+}
+
+// settings App 级别的运行设置，以 Feature 形式存放在 Runtime 中
+type settings struct {
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+	reloadSignals   []os.Signal
+	upgradeSignals  []os.Signal
+	upgradeTimeout  time.Duration
+	// diagnosticsSignals 触发诊断快照的信号，diagnosticsFile 非空时快照追加到该文件
+	diagnosticsSignals []os.Signal
+	diagnosticsFile    string
+	stdout, stderr     io.Writer
+	// printTrace 是否在启动后输出启动耗时表，未设置时仅开发环境输出
+	printTrace *bool
+}
+
+// New 创建应用
+// 这一步会应用所有选项并构建 DI 容器，但不会启动生命周期。
+func New(opts ...core.Option) (*App, error) {
+	rt := core.NewRuntime()
+
+	// 1. Bootstrap (应用所有选项)
+	// 这一步会配置 Feature、注册服务、添加生命周期钩子等
+	if err := rt.Apply(opts...); err != nil {
+		return nil, err
+	}
+
+	// 2. Build DI Container (构建依赖注入容器)
+	// 构建失败时释放已经创建的单例
+	if err := rt.Build(); err != nil {
+		closeCtx, cancel := context.WithTimeout(context.Background(), settingsOf(rt).shutdownTimeout)
+		defer cancel()
+		return nil, errors.Join(err, rt.Container.Close(closeCtx))
+	}
+
+	a := &App{
+		rt:       rt,
+		settings: settingsOf(rt),
+		done:     make(chan struct{}),
+	}
+	if err := a.registerBuiltinCommands(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Runtime 返回应用的运行时
+func (a *App) Runtime() *core.Runtime {
+	return a.rt
+}
+
+// Start 启动生命周期，并在后台监听退出信号
+// 收到信号或运行时请求退出 (rt.Shutdown) 时，App 会在配置的超时时间内自动停止。
+// 启动失败时已启动的钩子会被回滚，Wait 将立即返回该错误。
+func (a *App) Start(ctx context.Context) error {
+	a.mu.Lock()
+	if a.started {
+		a.mu.Unlock()
+		return errors.New("app: already started")
+	}
+	a.started = true
+	a.mu.Unlock()
+
+	err := a.rt.Lifecycle.Start(ctx, a.rt.Container)
+	if a.shouldPrintTrace() {
+		fmt.Fprintln(a.settings.stderr, "Startup trace:")
+		_ = a.rt.StartupTrace().WriteTable(a.settings.stderr)
+	}
+	if err != nil {
+		a.stopOnce.Do(func() {
+			a.stopErr = err
+			close(a.done)
+		})
+		return err
+	}
+
+	// 由优雅升级启动时，通知父进程开始排空并退出
+	if err := core.UpgradeReady(); err != nil && a.rt.ErrorHandler != nil {
+		a.rt.ErrorHandler(err)
+	}
+
+	go a.watch()
+	return nil
+}
+
+// shouldPrintTrace 判断是否输出启动耗时表
+func (a *App) shouldPrintTrace() bool {
+	if a.settings.printTrace != nil {
+		return *a.settings.printTrace
+	}
+	env := core.EnvironmentFrom(a.rt)
+	return env != nil && env.IsDevelopment()
+}
+
+// Stop 优雅停止应用，可重复调用
+// 并发调用时，后续调用会等待第一次停止完成并返回相同的结果。
+func (a *App) Stop(ctx context.Context) error {
+	a.stopOnce.Do(func() {
+		// 标记运行时进入退出流程，唤醒所有监听 rt.Done() 的组件
+		a.rt.Shutdown()
+		a.stopErr = a.rt.Lifecycle.Stop(ctx)
+		close(a.done)
+	})
+	<-a.done
+	return a.stopErr
+}
+
+// Wait 阻塞直到应用停止
+// 返回触发退出的错误 (见 Err) 与停止过程中产生的错误的组合。
+func (a *App) Wait() error {
+	<-a.done
+	return errors.Join(a.Err(), a.stopErr)
+}
+
+// Err 返回导致应用退出的错误，例如崩溃的托管服务
+// 由信号或 Stop 触发的正常退出返回 nil。
+func (a *App) Err() error {
+	return a.rt.Err()
+}
+
+// watch 等待退出信号并触发优雅关闭
+func (a *App) watch() {
+	// 支持 OS 信号 (Ctrl+C, kill) 和 Runtime 内部触发的退出 (rt.Shutdown)
+	quit := make(chan os.Signal, 1)
+	if len(a.settings.signals) > 0 {
+		signal.Notify(quit, a.settings.signals...)
+		defer signal.Stop(quit)
+	}
+
+	// 重新加载信号 (SIGHUP)
+	reload := make(chan os.Signal, 1)
+	if len(a.settings.reloadSignals) > 0 {
+		signal.Notify(reload, a.settings.reloadSignals...)
+		defer signal.Stop(reload)
+	}
+
+	// 诊断快照信号 (SIGUSR1)
+	diagnostics := make(chan os.Signal, 1)
+	if len(a.settings.diagnosticsSignals) > 0 {
+		signal.Notify(diagnostics, a.settings.diagnosticsSignals...)
+		defer signal.Stop(diagnostics)
+	}
+
+	// 优雅升级信号 (SIGUSR2)
+	upgrade := make(chan os.Signal, 1)
+	if len(a.settings.upgradeSignals) > 0 {
+		signal.Notify(upgrade, a.settings.upgradeSignals...)
+		defer signal.Stop(upgrade)
+	}
+
+wait:
+	for {
+		select {
+		case <-reload:
+			// 重新加载失败只报告错误，不退出
+			_ = a.rt.Reload()
+		case <-diagnostics:
+			a.dumpDiagnostics()
+		case <-upgrade:
+			// 新进程就绪后排空并退出，升级失败时继续运行
+			if a.upgrade() {
+				break wait
+			}
+		case <-quit:
+			// 收到系统信号
+			break wait
+		case <-a.rt.Done():
+			// 运行时内部请求退出 (例如关键服务崩溃)
+			break wait
+		case <-a.done:
+			// 已通过 Stop 停止
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.settings.shutdownTimeout)
+	defer cancel()
+
+	_ = a.Stop(ctx)
+}
+
+// dumpDiagnostics 输出诊断快照，配置了文件时追加到文件，否则交给 rt.DiagnosticsReporter
+func (a *App) dumpDiagnostics() {
+	if a.settings.diagnosticsFile == "" {
+		a.rt.DumpDiagnostics()
+		return
+	}
+
+	f, err := os.OpenFile(a.settings.diagnosticsFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err == nil {
+		_, err = a.rt.Diagnostics().WriteTo(f)
+		err = errors.Join(err, f.Close())
+	}
+	if err != nil && a.rt.ErrorHandler != nil {
+		a.rt.ErrorHandler(fmt.Errorf("app: failed to write diagnostics: %w", err))
+	}
+}
+
+// upgrade 启动新进程并交出监听器，返回新进程是否已就绪
+func (a *App) upgrade() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), a.settings.upgradeTimeout)
+	defer cancel()
+
+	pid, err := core.Upgrade(ctx)
+	if err != nil {
+		if a.rt.ErrorHandler != nil {
+			a.rt.ErrorHandler(err)
+		}
+		return false
+	}
+	fmt.Fprintf(a.settings.stderr, "Upgraded to pid %d, draining\n", pid)
+	return true
+}
+
+// WithShutdownTimeout 设置优雅关闭的超时时间（默认 5 秒）
+func WithShutdownTimeout(timeout time.Duration) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).shutdownTimeout = timeout
+		return nil
+	}
+}
+
+// WithSignals 设置触发优雅关闭的信号（默认 SIGINT 和 SIGTERM）
+// 不传任何信号表示不监听 OS 信号，只能通过 Stop 或 rt.Shutdown 退出。
+func WithSignals(signals ...os.Signal) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).signals = signals
+		return nil
+	}
+}
+
+// WithReloadSignals 设置触发重新加载 (Runtime.Reload) 的信号（默认 SIGHUP）
+// 不传任何信号表示不监听重新加载信号，只能通过 rt.Reload 触发。
+func WithReloadSignals(signals ...os.Signal) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).reloadSignals = signals
+		return nil
+	}
+}
+
+// WithGracefulUpgrade 启用零停机升级，收到信号时（默认 SIGUSR2）执行：
+//  1. 启动磁盘上的可执行文件（通常已替换为新版本），并将通过 core.Listen 创建的监听器（例如 Web 主机的端口）传给它；
+//  2. 新进程完成启动后通知当前进程；
+//  3. 当前进程停止接收新连接，排空进行中的请求后退出。
+//
+// 新进程在就绪前退出或超过升级超时时间（见 WithUpgradeTimeout）时升级失败，当前进程继续运行。
+// 仅支持 Linux 等 Unix 系统，不依赖 systemd 等进程管理器。
+func WithGracefulUpgrade(signals ...os.Signal) core.Option {
+	return func(rt *core.Runtime) error {
+		if len(signals) == 0 {
+			signals = defaultUpgradeSignals
+		}
+		if len(signals) == 0 {
+			return errors.New("app: graceful upgrade is not supported on this platform")
+		}
+		settingsOf(rt).upgradeSignals = signals
+		return nil
+	}
+}
+
+// WithUpgradeTimeout 设置等待新进程就绪的超时时间（默认 1 分钟）
+func WithUpgradeTimeout(timeout time.Duration) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).upgradeTimeout = timeout
+		return nil
+	}
+}
+
+// WithDiagnosticsSignals 设置触发诊断快照 (Runtime.DumpDiagnostics) 的信号（默认 SIGUSR1）
+// 不传任何信号表示不监听诊断信号，只能通过 rt.Diagnostics 获取。
+func WithDiagnosticsSignals(signals ...os.Signal) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).diagnosticsSignals = signals
+		return nil
+	}
+}
+
+// WithDiagnosticsFile 将收到信号时生成的诊断快照追加到文件，而不是输出到日志
+func WithDiagnosticsFile(path string) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).diagnosticsFile = path
+		return nil
+	}
+}
+
+// WithStartupTrace 设置是否在启动后输出启动耗时表（默认仅开发环境输出）
+// 耗时表包含每个 Option 与模块的应用、每个单例的构建以及每个启动钩子的执行耗时，
+// 也可以通过 rt.StartupTrace() 以 JSON 格式获取。
+func WithStartupTrace(enabled bool) core.Option {
+	return func(rt *core.Runtime) error {
+		settingsOf(rt).printTrace = &enabled
+		return nil
+	}
+}
+
+// settingsOf 获取 Runtime 中的 App 设置，不存在时创建默认设置
+func settingsOf(rt *core.Runtime) *settings {
+	if s := core.GetFeature[*settings](rt); s != nil {
+		return s
+	}
+	s := &settings{
+		shutdownTimeout:    5 * time.Second,
+		signals:            []os.Signal{os.Interrupt, syscall.SIGTERM},
+		reloadSignals:      []os.Signal{syscall.SIGHUP},
+		upgradeTimeout:     time.Minute,
+		diagnosticsSignals: defaultDiagnosticsSignals,
+		stdout:             os.Stdout,
+		stderr:             os.Stderr,
+	}
+	rt.Features.Set(s)
+	return s
+}
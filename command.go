@@ -0,0 +1,206 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+// Command 命令行子命令，见 core.Command
+type Command = core.Command
+
+// WithCommand 注册子命令
+func WithCommand(cmd Command) core.Option {
+	return core.WithCommand(cmd)
+}
+
+// WithOutput 设置命令与帮助信息的输出（默认 os.Stdout 与 os.Stderr）
+func WithOutput(stdout, stderr io.Writer) core.Option {
+	return func(rt *core.Runtime) error {
+		s := settingsOf(rt)
+		s.stdout, s.stderr = stdout, stderr
+		return nil
+	}
+}
+
+// Execute 根据命令行参数执行子命令
+// 未指定命令时执行 serve（启动应用并阻塞直到退出）。
+// serve 以外的命令返回后会关闭 DI 容器，释放命令使用的数据库连接等资源。
+// 全局参数 --env 由 core.Environment 解析，此处会被忽略。
+func (a *App) Execute(ctx context.Context, args []string) error {
+	args = stripGlobalFlags(args)
+	commands := core.CommandsFrom(a.rt)
+
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			if cmd, _ := commands.Find(args[1:]); cmd != nil {
+				cmd.FlagSet(a.settings.stderr).Usage()
+				return flag.ErrHelp
+			}
+			a.printHelp(a.settings.stderr)
+			return core.Usagef("unknown command %q", strings.Join(args[1:], " "))
+		}
+		a.printHelp(a.settings.stderr)
+		return flag.ErrHelp
+	}
+
+	cmd, rest := commands.Find(args)
+	if cmd == nil {
+		a.printHelp(a.settings.stderr)
+		return core.Usagef("unknown command %q", args[0])
+	}
+
+	inv := &core.Invocation{
+		Runtime: a.rt,
+		Stdout:  a.settings.stdout,
+		Stderr:  a.settings.stderr,
+	}
+	err := cmd.Execute(ctx, inv, rest)
+	if cmd.Name == "serve" {
+		// serve 在停止应用时已释放容器
+		return err
+	}
+
+	// 其他命令不会启动生命周期，需要在返回后释放容器创建的实例
+	closeCtx, cancel := context.WithTimeout(context.Background(), a.settings.shutdownTimeout)
+	defer cancel()
+	return errors.Join(err, a.rt.Container.Close(closeCtx))
+}
+
+// printHelp 输出所有子命令
+func (a *App) printHelp(w io.Writer) {
+	name := "app"
+	if env := core.EnvironmentFrom(a.rt); env != nil && env.AppName != "" {
+		name = env.AppName
+	}
+
+	fmt.Fprintf(w, "Usage: %s [--env <name>] <command> [flags] [args]\n\nCommands:\n", name)
+	for _, cmd := range core.CommandsFrom(a.rt).All() {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.Name, cmd.Short)
+	}
+	fmt.Fprintf(w, "\nRun '%s help <command>' for more information on a command.\n", name)
+}
+
+// stripGlobalFlags 移除位于命令之前的全局参数
+func stripGlobalFlags(args []string) []string {
+	for len(args) > 0 {
+		switch {
+		case args[0] == "--env" || args[0] == "-env":
+			if len(args) < 2 {
+				return nil
+			}
+			args = args[2:]
+		case strings.HasPrefix(args[0], "--env=") || strings.HasPrefix(args[0], "-env="):
+			args = args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// Main 根据命令行参数执行子命令，并以对应的退出码退出进程
+// 用法: func main() { app.Main(opts...) }
+func Main(opts ...core.Option) {
+	a, err := New(opts...)
+	if err == nil {
+		err = a.Execute(context.Background(), os.Args[1:])
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(ExitCode(err))
+}
+
+// ExitCode 返回错误对应的进程退出码
+// nil 与帮助请求为 0，使用错误 (core.UsageError) 为 2，其他错误为 1。
+func ExitCode(err error) int {
+	var usageErr *core.UsageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &usageErr):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// registerBuiltinCommands 注册内置子命令
+func (a *App) registerBuiltinCommands() error {
+	commands := core.CommandsFrom(a.rt)
+
+	if err := commands.Add(Command{
+		Name:  "serve",
+		Short: "Start the application and block until it exits (default)",
+		Run: func(ctx context.Context, inv *core.Invocation) error {
+			if len(inv.Args) > 0 {
+				return core.Usagef("serve: unexpected arguments %v", inv.Args)
+			}
+			if err := a.Start(ctx); err != nil {
+				return err
+			}
+			return a.Wait()
+		},
+	}); err != nil {
+		return err
+	}
+
+	var format string
+	return commands.Add(Command{
+		Name:  "di graph",
+		Short: "Print registered services and their dependencies",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&format, "format", "text", "output format: text or dot")
+		},
+		Run: func(ctx context.Context, inv *core.Invocation) error {
+			services := inv.Runtime.Container.Services()
+			switch format {
+			case "text":
+				for _, svc := range services {
+					fmt.Fprintf(inv.Stdout, "%v [%v]\n", svc.Key, svc.Scope)
+					for _, dep := range svc.Dependencies {
+						optional := ""
+						if dep.Optional {
+							optional = " (optional)"
+						}
+						fmt.Fprintf(inv.Stdout, "  -> %v%s\n", dep.Key, optional)
+					}
+				}
+			case "dot":
+				writeDot(inv.Stdout, services)
+			default:
+				return core.Usagef("di graph: unknown format %q", format)
+			}
+			return nil
+		},
+	})
+}
+
+// writeDot 以 Graphviz DOT 格式输出依赖图
+func writeDot(w io.Writer, services []di.ServiceInfo) {
+	fmt.Fprintln(w, "digraph services {")
+	for _, svc := range services {
+		fmt.Fprintf(w, "  %q;\n", svc.Key.String())
+		for _, dep := range svc.Dependencies {
+			style := ""
+			if dep.Optional {
+				style = " [style=dashed]"
+			}
+			fmt.Fprintf(w, "  %q -> %q%s;\n", svc.Key.String(), dep.Key.String(), style)
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
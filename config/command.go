@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gocrud/app/core"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted 敏感配置值的替代文本
+const redacted = "******"
+
+// sensitiveKeys 键名包含这些片段的配置值视为敏感信息，输出时会被隐藏
+var sensitiveKeys = []string{"password", "passwd", "pwd", "secret", "token", "credential", "apikey", "privatekey", "dsn"}
+
+// printCommand 创建 "config print" 子命令，以 YAML 格式输出合并后的配置
+// 密码、密钥、令牌等敏感值以及 URL 中的密码会被隐藏，--show-secrets 可以输出原始值。
+func printCommand(cfg Configuration) core.Command {
+	var section string
+	var showSecrets bool
+	return core.Command{
+		Name:  "config print",
+		Short: "Print the merged configuration as YAML",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&section, "section", "", "only print the given section, e.g. database")
+			fs.BoolVar(&showSecrets, "show-secrets", false, "print sensitive values such as passwords and tokens")
+		},
+		Run: func(ctx context.Context, inv *core.Invocation) error {
+			data := cfg.GetAll()
+			if section != "" {
+				data = cfg.GetSection(section).GetAll()
+				if len(data) == 0 {
+					return fmt.Errorf("config: section '%s' not found", section)
+				}
+			}
+			if !showSecrets {
+				data = redactMap(data)
+			}
+
+			out, err := yaml.Marshal(data)
+			if err != nil {
+				return fmt.Errorf("config: failed to marshal configuration: %w", err)
+			}
+			_, err = inv.Stdout.Write(out)
+			return err
+		},
+	}
+}
+
+// redactMap 返回隐藏了敏感值的配置副本
+// GetAll 返回的副本与配置共享嵌套的 map，因此这里不能就地修改。
+func redactMap(data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+	for key, value := range data {
+		if isSensitiveKey(key) {
+			// 环境变量 AWS_SECRET_ACCESS_KEY 会展开为嵌套的 aws.secret.access.key，整棵子树都需要隐藏
+			result[key] = redacted
+			continue
+		}
+		result[key] = redactValue(value)
+	}
+	return result
+}
+
+// redactValue 隐藏嵌套配置中的敏感值与 URL 中的密码
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactMap(v)
+	case []any:
+		items := make([]any, len(v))
+		for i := range v {
+			items[i] = redactValue(v[i])
+		}
+		return items
+	case string:
+		if u, err := url.Parse(v); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				return u.Redacted()
+			}
+		}
+	}
+	return value
+}
+
+// isSensitiveKey 判断键名是否表示敏感信息（忽略大小写与分隔符）
+func isSensitiveKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -39,6 +39,8 @@ type Configuration interface {
 type configuration struct {
 	data map[string]any
 	mu   sync.RWMutex
+	// generation 成功重新加载的次数
+	generation uint64
 }
 
 // NewConfiguration 创建新的配置实例
@@ -78,6 +80,19 @@ func (c *configuration) LoadFile(path string) error {
 	return nil
 }
 
+// replace 以 fresh 的数据整体替换当前配置
+// 读者在锁保护下要么看到旧配置，要么看到新配置，不会看到加载到一半的数据。
+func (c *configuration) replace(fresh *configuration) {
+	fresh.mu.RLock()
+	data := fresh.data
+	fresh.mu.RUnlock()
+
+	c.mu.Lock()
+	c.data = data
+	c.generation++
+	c.mu.Unlock()
+}
+
 // LoadEnv 加载环境变量
 func (c *configuration) LoadEnv(prefix ...string) {
 	c.mu.Lock()
@@ -3,6 +3,11 @@ package config
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
@@ -10,81 +15,232 @@ import (
 
 // LoadOptions 配置加载选项
 type LoadOptions struct {
-	Paths       []string
-	HotReload   bool
+	Paths        []string
+	HotReload    bool
 	KeyDelimiter string
+	// PollInterval 热重载检查文件变化的间隔，默认 2 秒
+	PollInterval time.Duration
 }
 
 // LoadOption 配置加载选项函数
 type LoadOption func(*LoadOptions)
 
 // WithHotReload 启用热重载
+// 配置文件发生变化时自动触发 Runtime.Reload，效果与发送 SIGHUP 相同。
 func WithHotReload() LoadOption {
 	return func(o *LoadOptions) {
 		o.HotReload = true
 	}
 }
 
+// WithFiles 追加配置文件，后加载的文件覆盖先加载的同名配置项
+func WithFiles(paths ...string) LoadOption {
+	return func(o *LoadOptions) {
+		o.Paths = append(o.Paths, paths...)
+	}
+}
+
 // Load 加载配置文件
 // 支持 YAML, JSON (通过 YAML 解析器兼容)
+// 相对路径基于 Environment.ContentRoot 查找；若存在 <name>.<env>.<ext> 形式的环境配置文件，
+// 会在基础配置之上叠加加载，环境变量的优先级最高。
+// 重新加载 (SIGHUP 或 Runtime.Reload) 时会重新读取所有文件并整体替换配置数据，
+// 读取失败时保留原有配置。
+// Load 注册名为 "config" 的模块，只能调用一次，再次调用时应用构建失败
+// (module "config" is already registered by a different instance)；加载多个文件请在同一次调用中使用 WithFiles。
 func Load(path string, opts ...LoadOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("config", func(rt *core.Runtime) error {
 		options := &LoadOptions{
 			Paths:        []string{path},
 			HotReload:    false,
 			KeyDelimiter: ":",
+			PollInterval: 2 * time.Second,
 		}
 		for _, opt := range opts {
 			opt(options)
 		}
 
-		// 创建 Configuration 实例
-		cfg := NewConfiguration()
-		
-		// 加载文件
+		// 解析文件路径，随后叠加当前环境的配置文件 (例如 config.Development.yaml)
+		env := core.EnvironmentFrom(rt)
+		paths := make([]string, 0, len(options.Paths))
 		for _, p := range options.Paths {
-			if err := cfg.LoadFile(p); err != nil {
-				// 暂时忽略文件不存在错误? 或者根据策略
-				// 这里简单的打印错误
-				fmt.Printf("config: failed to load %s: %v\n", p, err)
-				// return err // 如果是必需的配置文件，应该返回错误
+			if env != nil && !filepath.IsAbs(p) {
+				p = filepath.Join(env.ContentRoot, p)
 			}
+			paths = append(paths, p)
+		}
+		envName := ""
+		if env != nil {
+			envName = env.Name
 		}
 
-		// 加载环境变量
-		cfg.LoadEnv()
+		// 创建 Configuration 实例
+		cfg, err := loadConfiguration(paths, envName, false)
+		if err != nil {
+			return err
+		}
 
 		// 注册 Configuration 到 DI 容器
 		// 同时支持 Configuration 接口和具体结构体
-		di.ProvideService[Configuration](rt.Container, di.WithValue(cfg))
+		di.ProvideService[Configuration](rt.Container, di.WithValue(Configuration(cfg)))
 		// rt.Provide(cfg) // 也可以注册 *configuration，但通常接口就够了
 
 		// 注册为 Runtime Feature
 		rt.Features.Set(cfg)
 
-		// 如果启用了热重载，启动监听
+		// 注册 "config print" 子命令
+		if err := core.CommandsFrom(rt).Add(printCommand(cfg)); err != nil {
+			return err
+		}
+
+		// 重新加载：读取失败时保留原有配置
+		hook := core.Hook{
+			Name:  "config",
+			Stage: core.StageInfrastructure,
+			OnReload: func(ctx context.Context) error {
+				fresh, err := loadConfiguration(paths, envName, true)
+				if err != nil {
+					return err
+				}
+				cfg.replace(fresh)
+				return nil
+			},
+		}
+
+		// 如果启用了热重载，轮询文件变化并触发重新加载
 		if options.HotReload {
-			rt.Lifecycle.OnStart(func(ctx context.Context) error {
-				// TODO: 实现文件监听 (fsnotify)
-				// 这里暂时留空
+			var stop context.CancelFunc
+			hook.OnStart = func(ctx context.Context) error {
+				var watchCtx context.Context
+				watchCtx, stop = context.WithCancel(context.Background())
+				go watchFiles(watchCtx, paths, envName, options.PollInterval, func() {
+					_ = rt.Reload()
+				})
+				return nil
+			}
+			hook.OnStop = func(ctx context.Context) error {
+				if stop != nil {
+					stop()
+				}
 				return nil
-			})
+			}
 		}
+		rt.Lifecycle.Append(hook)
 
 		return nil
+	}))
+}
+
+// loadConfiguration 加载配置文件与环境变量
+// strict 为 false 时，基础配置文件读取失败只打印错误（首次加载时兼容缺失的文件）。
+func loadConfiguration(paths []string, env string, strict bool) (*configuration, error) {
+	cfg := NewConfiguration().(*configuration)
+
+	for _, p := range paths {
+		if err := cfg.LoadFile(p); err != nil {
+			if strict {
+				return nil, fmt.Errorf("config: failed to load %s: %w", p, err)
+			}
+			// 暂时忽略文件不存在错误? 或者根据策略
+			// 这里简单的打印错误
+			fmt.Printf("config: failed to load %s: %v\n", p, err)
+		}
+
+		if envPath := environmentFile(p, env); envPath != "" {
+			if err := cfg.LoadFile(envPath); err != nil {
+				return nil, fmt.Errorf("config: failed to load %s: %w", envPath, err)
+			}
+		}
 	}
+
+	// 加载环境变量
+	cfg.LoadEnv()
+	return cfg, nil
+}
+
+// watchFiles 定期检查配置文件（包括环境配置文件）的修改时间，发生变化时调用 onChange
+func watchFiles(ctx context.Context, paths []string, env string, interval time.Duration, onChange func()) {
+	snapshot := func() map[string]time.Time {
+		mtimes := make(map[string]time.Time)
+		for _, p := range paths {
+			for _, f := range []string{p, environmentFile(p, env)} {
+				if f == "" {
+					continue
+				}
+				if info, err := os.Stat(f); err == nil {
+					mtimes[f] = info.ModTime()
+				}
+			}
+		}
+		return mtimes
+	}
+
+	last := snapshot()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := snapshot()
+			if !maps.Equal(last, current) {
+				last = current
+				onChange()
+			}
+		}
+	}
+}
+
+// environmentFile 返回 path 对应的环境配置文件路径，不存在时返回空字符串
+// 先查找与环境名称大小写一致的文件，再查找小写形式，例如 config.Development.yaml、config.development.yaml。
+func environmentFile(path, env string) string {
+	if env == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for _, name := range []string{env, strings.ToLower(env)} {
+		candidate := base + "." + name + ext
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// From 获取 Runtime 中已加载的配置，未调用 Load 时返回 nil
+// 供需要在构建阶段或重新加载时读取配置的模块使用。
+func From(rt *core.Runtime) Configuration {
+	if cfg := core.GetFeature[*configuration](rt); cfg != nil {
+		return cfg
+	}
+	return nil
+}
+
+// Generation 返回配置成功重新加载的次数，未调用 Load 时返回 0
+// 供需要感知配置变化的模块在较晚阶段的 OnReload 中判断本次重新加载是否成功。
+func Generation(rt *core.Runtime) uint64 {
+	cfg := core.GetFeature[*configuration](rt)
+	if cfg == nil {
+		return 0
+	}
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.generation
 }
 
 // Bind 将配置绑定到结构体并注册到 DI 容器
+// 绑定在解析 *T 时才发生，因此与 Load 的注册顺序无关。
+// 绑定结果是单例快照，重新加载配置后不会更新；需要最新值时请直接读取 Configuration。
 func Bind[T any](rt *core.Runtime, section string) error {
-	return rt.Invoke(func(cfg Configuration) error {
+	// 注册为单例
+	return rt.Provide(func(cfg Configuration) (*T, error) {
 		var settings T
 		if err := cfg.Bind(section, &settings); err != nil {
-			return fmt.Errorf("config: failed to bind section '%s': %w", section, err)
+			return nil, fmt.Errorf("config: failed to bind section '%s': %w", section, err)
 		}
-
-		// 注册为单例
-		return rt.Provide(&settings)
+		return &settings, nil
 	})
 }
-
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Command 命令行子命令
+// 子命令在 DI 容器构建完成后执行，但不会启动生命周期钩子（托管服务、Web 主机等），
+// 适用于数据库迁移、执行一次定时任务、打印配置等一次性操作。
+type Command struct {
+	// Name 命令名称，多级命令以空格分隔，例如 "migrate up"
+	Name string
+	// Usage 位置参数说明，例如 "<job>"
+	Usage string
+	// Short 一行说明，显示在帮助列表中
+	Short string
+	// Flags 定义命令的参数
+	Flags func(fs *flag.FlagSet)
+	// Run 执行命令，flag 解析后剩余的位置参数通过 Invocation.Args 传入
+	Run func(ctx context.Context, inv *Invocation) error
+}
+
+// Invocation 一次命令调用
+type Invocation struct {
+	Runtime *Runtime
+	// Args 解析参数后剩余的位置参数
+	Args []string
+	// Stdout 命令的标准输出
+	Stdout io.Writer
+	// Stderr 命令的错误输出
+	Stderr io.Writer
+}
+
+// UsageError 表示命令的使用方式有误（未知命令、参数错误等）
+// Command.Execute 返回此类错误前会输出命令帮助，app.Main 对此类错误以退出码 2 退出。
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Usagef 创建 UsageError
+func Usagef(format string, args ...any) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+// CommandSet 子命令注册表，以 Feature 形式存放在 Runtime 中
+type CommandSet struct {
+	mu       sync.RWMutex
+	commands []Command
+}
+
+// Add 注册子命令，名称必须唯一
+func (s *CommandSet) Add(cmd Command) error {
+	cmd.Name = strings.Join(strings.Fields(cmd.Name), " ")
+	if cmd.Name == "" {
+		return errors.New("command: name is required")
+	}
+	if cmd.Run == nil {
+		return fmt.Errorf("command: %q has no Run function", cmd.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.commands {
+		if existing.Name == cmd.Name {
+			return fmt.Errorf("command: %q already registered", cmd.Name)
+		}
+	}
+	s.commands = append(s.commands, cmd)
+	return nil
+}
+
+// Find 按最长前缀匹配命令，返回命令与剩余参数
+func (s *CommandSet) Find(args []string) (*Command, []string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *Command
+	var words int
+	for i := range s.commands {
+		parts := strings.Fields(s.commands[i].Name)
+		if len(parts) > len(args) || len(parts) <= words {
+			continue
+		}
+		if slices.Equal(parts, args[:len(parts)]) {
+			found, words = &s.commands[i], len(parts)
+		}
+	}
+	if found == nil {
+		return nil, args
+	}
+	return found, args[words:]
+}
+
+// All 返回按名称排序的所有命令
+func (s *CommandSet) All() []Command {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cmds := slices.Clone(s.commands)
+	slices.SortFunc(cmds, func(a, b Command) int { return strings.Compare(a.Name, b.Name) })
+	return cmds
+}
+
+// Execute 解析命令参数并执行命令
+// 参数错误或 Run 返回 UsageError 时输出命令帮助并返回 UsageError；-h/--help 会输出命令帮助并返回 flag.ErrHelp。
+func (cmd *Command) Execute(ctx context.Context, inv *Invocation, args []string) error {
+	fs := cmd.FlagSet(inv.Stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &UsageError{Err: fmt.Errorf("%s: %w", cmd.Name, err)}
+	}
+	inv.Args = fs.Args()
+
+	err := cmd.Run(ctx, inv)
+	// 参数解析错误时 flag 包已经输出过帮助
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		fs.Usage()
+	}
+	return err
+}
+
+// FlagSet 创建命令的参数集合，帮助信息输出到 w
+func (cmd *Command) FlagSet(w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	// --env 已由 Environment 解析，这里仅声明以便放在命令之后时也能通过解析
+	if fs.Lookup("env") == nil {
+		fs.String("env", "", "environment name (see core.Environment)")
+	}
+	fs.Usage = func() {
+		usage := cmd.Name
+		if cmd.Usage != "" {
+			usage += " " + cmd.Usage
+		}
+		fmt.Fprintf(w, "Usage: %s [flags]\n", usage)
+		if cmd.Short != "" {
+			fmt.Fprintf(w, "\n%s\n", cmd.Short)
+		}
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(w, "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// CommandsFrom 获取 Runtime 中的子命令注册表，不存在时创建
+func CommandsFrom(rt *Runtime) *CommandSet {
+	if set := GetFeature[*CommandSet](rt); set != nil {
+		return set
+	}
+	set := &CommandSet{}
+	rt.Features.Set(set)
+	return set
+}
+
+// WithCommand 注册子命令
+func WithCommand(cmd Command) Option {
+	return func(rt *Runtime) error {
+		return CommandsFrom(rt).Add(cmd)
+	}
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"text/tabwriter"
+	"time"
+)
+
+// Diagnostics 运行时诊断快照
+// 用于排查运行中的进程，可通过 JSON 或 WriteTo 的文本格式输出。
+type Diagnostics struct {
+	Time time.Time `json:"time"`
+	// Services DI 容器中的所有注册，按注册顺序排列
+	Services []ServiceState `json:"services"`
+	// Hooks 生命周期钩子的运行状态，按注册顺序排列
+	Hooks []HookState `json:"hooks"`
+	// Features FeatureCollection 中所有特性的类型
+	Features []string `json:"features"`
+	// Sections 模块通过 AddDiagnostics 提供的诊断信息，例如定时任务与连接池状态
+	Sections []DiagnosticsSection `json:"sections"`
+	// Goroutines 所有 Goroutine 的调用栈
+	Goroutines string `json:"goroutines"`
+}
+
+// ServiceState DI 容器中一个服务的状态
+type ServiceState struct {
+	Service string `json:"service"`
+	Scope   string `json:"scope"`
+	// Instantiated 单例是否已经创建
+	Instantiated bool `json:"instantiated"`
+}
+
+// DiagnosticsSection 模块提供的一段诊断信息
+type DiagnosticsSection struct {
+	Name string `json:"name"`
+	Data any    `json:"data"`
+}
+
+// diagnosticsSource 通过 AddDiagnostics 注册的诊断信息来源
+type diagnosticsSource struct {
+	name    string
+	collect func() any
+}
+
+// AddDiagnostics 注册诊断信息
+// collect 在每次生成诊断快照时调用，返回值需要能够以 JSON 格式输出；panic 会被记录为错误信息。
+func (rt *Runtime) AddDiagnostics(name string, collect func() any) {
+	rt.diagMu.Lock()
+	defer rt.diagMu.Unlock()
+	rt.diagnostics = append(rt.diagnostics, diagnosticsSource{name: name, collect: collect})
+}
+
+// Diagnostics 生成当前的诊断快照
+func (rt *Runtime) Diagnostics() Diagnostics {
+	d := Diagnostics{Time: time.Now()}
+
+	for _, info := range rt.Container.Services() {
+		d.Services = append(d.Services, ServiceState{
+			Service:      info.Key.String(),
+			Scope:        info.Scope.String(),
+			Instantiated: info.Instantiated,
+		})
+	}
+	d.Hooks = rt.Lifecycle.Hooks()
+	for _, typ := range rt.Features.Types() {
+		d.Features = append(d.Features, typ.String())
+	}
+
+	rt.diagMu.Lock()
+	sources := rt.diagnostics
+	rt.diagMu.Unlock()
+	for _, source := range sources {
+		var data any
+		if err := safeRun("diagnostics "+source.name, func() error {
+			data = source.collect()
+			return nil
+		}); err != nil {
+			data = err.Error()
+		}
+		d.Sections = append(d.Sections, DiagnosticsSection{Name: source.name, Data: data})
+	}
+
+	d.Goroutines = goroutineStacks()
+	return d
+}
+
+// DumpDiagnostics 生成诊断快照并交给 DiagnosticsReporter，未设置时输出到标准错误
+func (rt *Runtime) DumpDiagnostics() {
+	d := rt.Diagnostics()
+	if rt.DiagnosticsReporter != nil {
+		rt.DiagnosticsReporter(d)
+		return
+	}
+	_, _ = d.WriteTo(os.Stderr)
+}
+
+// WriteTo 以文本格式输出诊断快照
+func (d Diagnostics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "=== Runtime diagnostics at %s\n", d.Time.Format(time.RFC3339))
+
+	fmt.Fprintf(&buf, "\n--- Services (%d)\n", len(d.Services))
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tSCOPE\tINSTANTIATED")
+	for _, s := range d.Services {
+		fmt.Fprintf(tw, "%s\t%s\t%t\n", s.Service, s.Scope, s.Instantiated)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(&buf, "\n--- Lifecycle hooks (%d)\n", len(d.Hooks))
+	tw = tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTAGE\tSTATUS")
+	for _, h := range d.Hooks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", h.Name, h.Stage, h.Status)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(&buf, "\n--- Features (%d)\n", len(d.Features))
+	for _, f := range d.Features {
+		fmt.Fprintln(&buf, f)
+	}
+
+	for _, section := range d.Sections {
+		fmt.Fprintf(&buf, "\n--- %s\n", section.Name)
+		data, err := json.MarshalIndent(section.Data, "", "  ")
+		if err != nil {
+			fmt.Fprintf(&buf, "error: %v\n", err)
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	fmt.Fprintf(&buf, "\n--- Goroutines\n%s", d.Goroutines)
+	return buf.WriteTo(w)
+}
+
+// goroutineStacks 返回所有 Goroutine 的调用栈
+func goroutineStacks() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,75 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+type reportCache struct{}
+
+func TestDiagnostics(t *testing.T) {
+	rt := core.NewRuntime()
+	err := rt.Apply(func(rt *core.Runtime) error {
+		rt.Lifecycle.OnStart(func(ctx context.Context) error { return nil }, core.WithHookName("warmup"))
+		rt.AddDiagnostics("pool", func() any { return map[string]int{"open": 3} })
+		rt.AddDiagnostics("broken", func() any { panic("boom") })
+		return rt.Provide(func() *reportCache { return &reportCache{} }, di.WithScoped())
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := rt.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if err := rt.Lifecycle.Start(context.Background(), rt.Container); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	d := rt.Diagnostics()
+	services := map[string]core.ServiceState{}
+	for _, s := range d.Services {
+		services[s.Service] = s
+	}
+	if s := services["*core_test.reportCache"]; s.Scope != "scoped" || s.Instantiated {
+		t.Errorf("Unexpected scoped service state %+v", s)
+	}
+	if s := services["*core.Environment"]; !s.Instantiated {
+		t.Errorf("Expected registered value to be instantiated, got %+v", s)
+	}
+	if len(d.Hooks) != 1 || d.Hooks[0].Name != "warmup" || d.Hooks[0].Status != core.HookStarted {
+		t.Errorf("Unexpected hook states %+v", d.Hooks)
+	}
+	if len(d.Sections) != 2 || !strings.Contains(d.Sections[1].Data.(string), "boom") {
+		t.Errorf("Expected panicking section to be reported, got %+v", d.Sections)
+	}
+	if !strings.Contains(d.Goroutines, "TestDiagnostics") {
+		t.Error("Expected goroutine stacks to include the test")
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"stage":"services"`) {
+		t.Errorf("Expected stage name in JSON, got %s", data)
+	}
+
+	var text bytes.Buffer
+	if _, err := d.WriteTo(&text); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "--- pool") || !strings.Contains(text.String(), `"open": 3`) {
+		t.Errorf("Expected section in text output, got\n%s", text.String())
+	}
+
+	_ = rt.Lifecycle.Stop(context.Background())
+	if hooks := rt.Diagnostics().Hooks; hooks[0].Status != core.HookStopped {
+		t.Errorf("Expected hook to be stopped, got %+v", hooks)
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+const (
+	// EnvDevelopment 开发环境
+	EnvDevelopment = "Development"
+	// EnvStaging 预发布环境
+	EnvStaging = "Staging"
+	// EnvProduction 生产环境（默认）
+	EnvProduction = "Production"
+)
+
+// Environment 应用运行环境
+// 框架会自动将其注册到 DI 容器和 Runtime Features 中，
+// 配置、Web、日志等模块根据运行环境选择默认行为。
+type Environment struct {
+	// AppName 应用名称，默认读取 APP_NAME，否则为可执行文件名
+	AppName string
+	// Name 环境名称，默认读取 --env 命令行参数或 APP_ENV，否则为 Production
+	Name string
+	// ContentRoot 内容根目录，相对路径的配置文件基于此目录查找
+	// 默认读取 APP_CONTENT_ROOT，否则为当前工作目录
+	ContentRoot string
+	// Version 应用版本，默认读取 APP_VERSION，否则为构建信息中的模块版本
+	Version string
+}
+
+// Is 判断当前是否为指定环境（忽略大小写）
+func (e *Environment) Is(name string) bool {
+	return strings.EqualFold(e.Name, name)
+}
+
+// IsDevelopment 判断是否为开发环境
+func (e *Environment) IsDevelopment() bool {
+	return e.Is(EnvDevelopment)
+}
+
+// IsStaging 判断是否为预发布环境
+func (e *Environment) IsStaging() bool {
+	return e.Is(EnvStaging)
+}
+
+// IsProduction 判断是否为生产环境
+func (e *Environment) IsProduction() bool {
+	return e.Is(EnvProduction)
+}
+
+// NewEnvironment 从命令行参数与环境变量解析运行环境
+func NewEnvironment() *Environment {
+	env := &Environment{
+		AppName:     os.Getenv("APP_NAME"),
+		Name:        envFlag(os.Args[1:]),
+		ContentRoot: os.Getenv("APP_CONTENT_ROOT"),
+		Version:     os.Getenv("APP_VERSION"),
+	}
+
+	if env.AppName == "" && len(os.Args) > 0 {
+		env.AppName = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	}
+	if env.Name == "" {
+		env.Name = os.Getenv("APP_ENV")
+	}
+	if env.Name == "" {
+		env.Name = EnvProduction
+	}
+	if env.ContentRoot == "" {
+		env.ContentRoot, _ = os.Getwd()
+	}
+	if env.Version == "" {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "(devel)" {
+			env.Version = info.Main.Version
+		}
+	}
+	return env
+}
+
+// envFlag 从命令行参数中查找 --env 的值，支持 --env=dev 与 --env dev 两种形式
+// 这里不使用 flag 包，以免与应用自身的命令行参数解析冲突。
+func envFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "env" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// EnvironmentFrom 获取 Runtime 中的运行环境
+func EnvironmentFrom(rt *Runtime) *Environment {
+	return GetFeature[*Environment](rt)
+}
+
+// WithEnvironment 修改运行环境
+// 应放在其他模块之前，确保模块读取到的是修改后的环境。
+func WithEnvironment(configure func(env *Environment)) Option {
+	return func(rt *Runtime) error {
+		configure(EnvironmentFrom(rt))
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+func TestEnvironment(t *testing.T) {
+	t.Setenv("APP_ENV", "staging")
+	t.Setenv("APP_NAME", "orders")
+	t.Setenv("APP_VERSION", "1.2.3")
+
+	rt := core.NewRuntime()
+	if err := rt.Container.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	env, err := di.Get[*core.Environment](rt.Container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if !env.IsStaging() || env.IsProduction() {
+		t.Errorf("Expected Staging environment, got %s", env.Name)
+	}
+	if env.AppName != "orders" || env.Version != "1.2.3" {
+		t.Errorf("Unexpected environment: %+v", env)
+	}
+	if env.ContentRoot == "" {
+		t.Error("Expected ContentRoot to default to the working directory")
+	}
+	if core.EnvironmentFrom(rt) != env {
+		t.Error("Expected the same Environment in Features and DI")
+	}
+}
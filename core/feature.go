@@ -2,6 +2,8 @@ package core
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -11,7 +13,7 @@ type FeatureCollection struct {
 	features sync.Map
 }
 
-// Set 注册一个特性
+// Set 注册一个特性，同类型的特性会被覆盖
 func (fc *FeatureCollection) Set(feature any) {
 	typ := reflect.TypeOf(feature)
 	fc.features.Store(typ, feature)
@@ -22,6 +24,19 @@ func (fc *FeatureCollection) Get(typ reflect.Type) (any, bool) {
 	return fc.features.Load(typ)
 }
 
+// Types 按类型名称排序返回所有已注册特性的类型
+func (fc *FeatureCollection) Types() []reflect.Type {
+	var types []reflect.Type
+	fc.features.Range(func(key, _ any) bool {
+		types = append(types, key.(reflect.Type))
+		return true
+	})
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return types
+}
+
 // GetFeature 泛型辅助函数，从 Runtime 获取特性
 func GetFeature[T any](rt *Runtime) T {
 	var zero T
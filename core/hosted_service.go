@@ -1,6 +1,9 @@
 package core
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // HostedService 定义了一个具有启动和停止生命周期的托管服务
 // 这是框架中所有后台服务的标准接口。
@@ -16,3 +19,54 @@ type HostedService interface {
 	// 必须支持通过 ctx 进行超时控制。
 	Stop(ctx context.Context) error
 }
+
+// ReadyNotifier 是托管服务可选实现的就绪通知接口
+// 实现该接口的服务在 Start 被调用后，框架会等待 Ready 返回的通道关闭，
+// 或 Start 返回错误，或等待超时，之后才继续启动后续钩子。
+// 未实现该接口的服务在 Start 被调用后立即视为就绪。
+type ReadyNotifier interface {
+	// Ready 返回一个在服务就绪（例如端口已开始监听）后关闭的通道
+	Ready() <-chan struct{}
+}
+
+// HostedOption 配置托管服务和后台 Worker 的运行方式
+type HostedOption func(*hostedOptions)
+
+// hostedOptions 托管服务运行选项
+type hostedOptions struct {
+	name         string
+	readyTimeout time.Duration
+	restart      RestartPolicy
+}
+
+// defaultReadyTimeout 等待服务就绪的默认超时时间
+const defaultReadyTimeout = 30 * time.Second
+
+func newHostedOptions(opts []HostedOption) *hostedOptions {
+	o := &hostedOptions{
+		readyTimeout: defaultReadyTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.readyTimeout <= 0 {
+		o.readyTimeout = defaultReadyTimeout
+	}
+	return o
+}
+
+// WithHostedName 设置托管服务或 Worker 的钩子名称，用于关闭报告、启动耗时与 After/Before 引用
+// 未设置时，托管服务使用服务类型名称，Worker 使用函数名称。
+func WithHostedName(name string) HostedOption {
+	return func(o *hostedOptions) {
+		o.name = name
+	}
+}
+
+// WithReadyTimeout 设置等待服务就绪的超时时间（默认 30 秒），小于等于 0 时使用默认值
+// 仅对实现了 ReadyNotifier 的服务生效。
+func WithReadyTimeout(timeout time.Duration) HostedOption {
+	return func(o *hostedOptions) {
+		o.readyTimeout = timeout
+	}
+}
@@ -2,54 +2,371 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/gocrud/app/di"
+	"golang.org/x/sync/errgroup"
 )
 
+// Hook 是一组成对的启动/停止钩子
+// 只有 OnStart 成功返回后，对应的 OnStop 才会在停止（或启动回滚）时被执行。
+// 只设置了 OnStop 的钩子在启动流程执行到它时即视为已启动。
+type Hook struct {
+	// Name 钩子名称，用于错误信息和关闭报告
+	Name string
+	// StopTimeout 单个 OnStop 的超时时间，实际截止时间不会超过 Stop 的整体截止时间
+	// 为 0 时仅受整体截止时间约束
+	StopTimeout time.Duration
+
+	// Stage 钩子所在的启动阶段，为 0 时视为 StageServices
+	Stage Stage
+	// After 同一阶段内必须先于本钩子启动的钩子名称
+	After []string
+	// Before 同一阶段内必须晚于本钩子启动的钩子名称
+	Before []string
+
+	OnStart func(context.Context) error
+	OnStop  func(context.Context) error
+	// OnReload 在收到重新加载请求 (SIGHUP 或 Runtime.Reload) 时执行，仅对已启动的钩子生效
+	OnReload func(context.Context) error
+}
+
+// HookOption 配置通过 OnStart/OnStop 注册的钩子
+type HookOption func(*Hook)
+
+// WithHookName 设置钩子名称
+func WithHookName(name string) HookOption {
+	return func(h *Hook) {
+		h.Name = name
+	}
+}
+
+// WithStopTimeout 设置停止钩子的独立超时时间
+func WithStopTimeout(timeout time.Duration) HookOption {
+	return func(h *Hook) {
+		h.StopTimeout = timeout
+	}
+}
+
 // LifecycleEvents 管理应用程序的生命周期
 type LifecycleEvents struct {
-	onStart []func(context.Context) error
-	onStop  []func(context.Context) error
+	mu      sync.Mutex
+	hooks   []Hook
+	started []int              // 已成功启动的钩子下标，按启动完成顺序排列
+	status  map[int]HookStatus // 钩子下标到运行状态，未记录的钩子尚未启动
+
+	// reporter 接收每次停止（包括启动回滚）生成的关闭报告
+	reporter func(ShutdownReport)
+
+	// reloadMu 保证同一时间只有一次重新加载
+	reloadMu sync.Mutex
+
+	// trace 记录每个启动钩子的耗时
+	trace *StartupTrace
+
+	// lifetime 在启动完成、开始停止和停止完成时发出通知
+	lifetime *applicationLifetime
+
+	// discover 在启动前从容器中发现托管服务并生成钩子，只执行一次
+	discover   func(di.Container) ([]Hook, error)
+	discovered bool
+
+	// dispose 在 Stop 或启动回滚执行完所有停止钩子后释放容器中的实例
+	dispose func(context.Context) error
+}
+
+// HookStatus 钩子的运行状态
+type HookStatus string
+
+const (
+	// HookPending 尚未启动
+	HookPending HookStatus = "pending"
+	// HookStarted 已启动
+	HookStarted HookStatus = "started"
+	// HookFailed 启动失败
+	HookFailed HookStatus = "failed"
+	// HookStopped 已停止
+	HookStopped HookStatus = "stopped"
+)
+
+// HookState 钩子的运行状态，用于诊断
+type HookState struct {
+	Name   string     `json:"name"`
+	Stage  Stage      `json:"stage"`
+	Status HookStatus `json:"status"`
+}
+
+// Hooks 按注册顺序返回所有钩子（包括已发现的托管服务）的运行状态
+func (l *LifecycleEvents) Hooks() []HookState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	states := make([]HookState, 0, len(l.hooks))
+	for i, hook := range l.hooks {
+		status, ok := l.status[i]
+		if !ok {
+			status = HookPending
+		}
+		states = append(states, HookState{Name: hookName(hook, i), Stage: hook.stage(), Status: status})
+	}
+	return states
+}
+
+// setStatus 记录钩子的运行状态
+func (l *LifecycleEvents) setStatus(i int, status HookStatus) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.status == nil {
+		l.status = make(map[int]HookStatus)
+	}
+	l.status[i] = status
 }
 
 // NewLifecycle 创建新的生命周期管理器
 func NewLifecycle() *LifecycleEvents {
 	return &LifecycleEvents{
-		onStart: make([]func(context.Context) error, 0),
-		onStop:  make([]func(context.Context) error, 0),
+		hooks: make([]Hook, 0),
 	}
 }
 
+// Append 注册一组成对的启动/停止钩子
+func (l *LifecycleEvents) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
 // OnStart 注册启动钩子
-func (l *LifecycleEvents) OnStart(fn func(context.Context) error) {
-	l.onStart = append(l.onStart, fn)
+func (l *LifecycleEvents) OnStart(fn func(context.Context) error, opts ...HookOption) {
+	hook := Hook{OnStart: fn}
+	for _, opt := range opts {
+		opt(&hook)
+	}
+	l.Append(hook)
 }
 
 // OnStop 注册停止钩子
-func (l *LifecycleEvents) OnStop(fn func(context.Context) error) {
-	l.onStop = append(l.onStop, fn)
+func (l *LifecycleEvents) OnStop(fn func(context.Context) error, opts ...HookOption) {
+	hook := Hook{OnStop: fn}
+	for _, opt := range opts {
+		opt(&hook)
+	}
+	l.Append(hook)
 }
 
 // Start 启动生命周期
+// 启动前会从容器中发现通过 di.AsHosted 标记的托管服务，并将其加入 StageHosts 阶段。
+// 钩子按阶段依次启动，同一阶段内声明了阶段或顺序的钩子并发启动；
+// 未声明阶段与顺序的钩子按注册顺序依次启动。
+// 任一钩子失败时，同阶段尚未开始的钩子不再启动，已开始的钩子执行完毕后，
+// 按倒序执行所有已启动钩子对应的停止钩子（回滚），最终返回启动错误与回滚错误的组合。
+// 启动失败时（包括钩子规划失败）同样会关闭 DI 容器，释放 Build 期间创建的单例。
 func (l *LifecycleEvents) Start(ctx context.Context, container di.Container) error {
-	// 这里未来可以加入从容器中解析 HostedService 的逻辑
-	for _, fn := range l.onStart {
-		if err := fn(ctx); err != nil {
+	l.mu.Lock()
+	dispose := l.dispose
+	l.mu.Unlock()
+
+	// 启动上下文可能已经取消，回滚与释放时不继承其取消信号
+	cleanupCtx := context.WithoutCancel(ctx)
+	abort := func(err error) error {
+		if dispose == nil {
+			return err
+		}
+		if disposeErr := safeRun("di container", func() error { return dispose(cleanupCtx) }); disposeErr != nil {
+			return errors.Join(err, fmt.Errorf("lifecycle: dispose container: %w", disposeErr))
+		}
+		return err
+	}
+
+	if err := l.discoverHooks(container); err != nil {
+		return abort(err)
+	}
+
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	plan, err := planStart(hooks)
+	if err != nil {
+		return abort(err)
+	}
+
+	for _, stage := range plan.stages {
+		if err := l.startStage(ctx, hooks, stage, plan.preds); err != nil {
+			if rollbackErr := l.stopStarted(cleanupCtx, dispose); rollbackErr != nil {
+				return errors.Join(err, fmt.Errorf("lifecycle: rollback failed: %w", rollbackErr))
+			}
 			return err
 		}
 	}
+
+	if l.lifetime != nil {
+		l.lifetime.started.fire()
+	}
+	return nil
+}
+
+// discoverHooks 将容器中标记为托管服务的注册加入钩子列表
+func (l *LifecycleEvents) discoverHooks(container di.Container) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.discovered || l.discover == nil || container == nil {
+		return nil
+	}
+	hooks, err := l.discover(container)
+	if err != nil {
+		return err
+	}
+	l.hooks = append(l.hooks, hooks...)
+	l.discovered = true
 	return nil
 }
 
+// startStage 并发启动同一阶段的钩子，遵循阶段内的 After/Before 顺序
+// 钩子收到的是调用方的 ctx，可以在启动后继续使用（例如启动后台循环）；
+// 同阶段的其他钩子失败时，只会放弃尚在等待前置钩子的启动。
+func (l *LifecycleEvents) startStage(ctx context.Context, hooks []Hook, stage []int, preds map[int][]int) error {
+	g, gctx := errgroup.WithContext(ctx)
+
+	done := make(map[int]chan struct{}, len(stage))
+	for _, i := range stage {
+		done[i] = make(chan struct{})
+	}
+
+	for _, i := range stage {
+		g.Go(func() error {
+			// 等待前置钩子完成，若同阶段已有钩子失败则放弃启动
+			for _, p := range preds[i] {
+				select {
+				case <-done[p]:
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+
+			hook := hooks[i]
+			if hook.OnStart != nil {
+				start := time.Now()
+				err := hook.OnStart(ctx)
+				if l.trace != nil {
+					l.trace.record(SpanHook, hookName(hook, i), start, err)
+				}
+				if err != nil {
+					l.setStatus(i, HookFailed)
+					return fmt.Errorf("lifecycle: start hook %s failed: %w", hookName(hook, i), err)
+				}
+			}
+
+			l.setStatus(i, HookStarted)
+			l.mu.Lock()
+			l.started = append(l.started, i)
+			l.mu.Unlock()
+			close(done[i])
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
 // Stop 停止生命周期
+// 按启动的相反顺序执行已启动钩子的停止逻辑，某个钩子失败或超时不会中断其他钩子。
+// 所有停止钩子执行完后关闭 DI 容器，释放容器创建的单例 (见 di.Disposable)。
+// 返回所有失败钩子与释放错误的组合，并生成一份只包含停止钩子的关闭报告。
 func (l *LifecycleEvents) Stop(ctx context.Context) error {
-	// 倒序执行停止钩子
-	for i := len(l.onStop) - 1; i >= 0; i-- {
-		fn := l.onStop[i]
-		if err := fn(ctx); err != nil {
-			// 记录错误但不中断，继续停止其他服务
-			// TODO: Log error
+	l.mu.Lock()
+	dispose := l.dispose
+	l.mu.Unlock()
+	return l.stopStarted(ctx, dispose)
+}
+
+// stopStarted 倒序执行所有已启动钩子的 OnStop，并清空启动记录
+// dispose 不为 nil 时在停止钩子之后、Stopped 通知之前执行，其错误不计入关闭报告。
+func (l *LifecycleEvents) stopStarted(ctx context.Context, dispose func(context.Context) error) error {
+	l.mu.Lock()
+	started := l.started
+	l.started = nil
+	hooks := l.hooks
+	reporter := l.reporter
+	lifetime := l.lifetime
+	l.mu.Unlock()
+
+	if lifetime != nil {
+		lifetime.stopping.fire()
+		defer lifetime.stopped.fire()
+	}
+
+	begin := time.Now()
+	report := ShutdownReport{}
+
+	for i := len(started) - 1; i >= 0; i-- {
+		hook := hooks[started[i]]
+		if hook.OnStop != nil {
+			report.Hooks = append(report.Hooks, runStopHook(ctx, hook, hookName(hook, started[i])))
 		}
+		l.setStatus(started[i], HookStopped)
 	}
-	return nil
+	report.Duration = time.Since(begin)
+
+	if reporter != nil {
+		reporter(report)
+	}
+
+	err := report.Err()
+	if dispose != nil {
+		if disposeErr := safeRun("di container", func() error { return dispose(ctx) }); disposeErr != nil {
+			err = errors.Join(err, fmt.Errorf("lifecycle: dispose container: %w", disposeErr))
+		}
+	}
+	return err
+}
+
+// runStopHook 在独立的超时上下文中执行单个停止钩子
+// 钩子若不响应 ctx 取消，将被视为超时并放弃等待，以免拖垮后续钩子。
+func runStopHook(ctx context.Context, hook Hook, name string) HookReport {
+	hookCtx := ctx
+	if hook.StopTimeout > 0 {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(ctx, hook.StopTimeout)
+		defer cancel()
+	}
+
+	begin := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- safeRun("hook "+name, func() error { return hook.OnStop(hookCtx) })
+	}()
+
+	result := HookReport{Name: name}
+	select {
+	case err := <-done:
+		result.Err = err
+		result.Outcome = OutcomeOK
+		if err != nil {
+			result.Outcome = OutcomeFailed
+			if errors.Is(err, context.DeadlineExceeded) {
+				result.Outcome = OutcomeTimeout
+			}
+		}
+	case <-hookCtx.Done():
+		result.Outcome = OutcomeTimeout
+		result.Err = hookCtx.Err()
+	}
+	result.Duration = time.Since(begin)
+	return result
+}
+
+// hookName 返回钩子的展示名称，未命名的钩子使用注册序号
+func hookName(hook Hook, index int) string {
+	if hook.Name != "" {
+		return hook.Name
+	}
+	return fmt.Sprintf("#%d", index)
 }
@@ -0,0 +1,277 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app/core"
+)
+
+func TestLifecycleStartRollback(t *testing.T) {
+	l := core.NewLifecycle()
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			calls = append(calls, name)
+			mu.Unlock()
+			return err
+		}
+	}
+
+	infra := core.InStage(core.StageInfrastructure)
+	l.Append(core.Hook{Name: "a", Stage: core.StageInfrastructure, OnStart: record("start-a", nil), OnStop: record("stop-a", nil)})
+	l.OnStop(record("stop-b", nil), core.WithHookName("b"), infra, core.After("a"))
+	l.Append(core.Hook{Name: "c", OnStart: record("start-c", errors.New("boom")), OnStop: record("stop-c", nil)})
+	l.Append(core.Hook{Name: "d", Stage: core.StageHosts, OnStart: record("start-d", nil), OnStop: record("stop-d", nil)})
+
+	err := l.Start(context.Background(), nil)
+	if err == nil {
+		t.Fatal("Expected start error, got nil")
+	}
+
+	expected := []string{"start-a", "start-c", "stop-b", "stop-a"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+
+	// 回滚后 Stop 不应重复执行停止钩子
+	calls = nil
+	if err := l.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no stop calls after rollback, got %v", calls)
+	}
+}
+
+func TestLifecycleParallelStage(t *testing.T) {
+	l := core.NewLifecycle()
+
+	// 两个钩子互相等待对方启动，只有并发执行时才能完成
+	aStarted, bStarted := make(chan struct{}), make(chan struct{})
+	wait := func(self, other chan struct{}) func(context.Context) error {
+		return func(ctx context.Context) error {
+			close(self)
+			select {
+			case <-other:
+				return nil
+			case <-time.After(time.Second):
+				return errors.New("hooks in the same stage did not run concurrently")
+			}
+		}
+	}
+	l.OnStart(wait(aStarted, bStarted), core.WithHookName("a"), core.InStage(core.StageServices))
+	l.OnStart(wait(bStarted, aStarted), core.WithHookName("b"), core.InStage(core.StageServices))
+
+	var order []string
+	l.OnStart(func(context.Context) error {
+		order = append(order, "last")
+		return nil
+	}, core.WithHookName("last"), core.After("a", "b"))
+	l.OnStart(func(context.Context) error {
+		order = append(order, "first")
+		return nil
+	}, core.WithHookName("first"), core.Before("a"), core.InStage(core.StageInfrastructure))
+
+	if err := l.Start(context.Background(), nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"first", "last"}) {
+		t.Errorf("Unexpected start order %v", order)
+	}
+}
+
+func TestLifecycleLegacyHooks(t *testing.T) {
+	l := core.NewLifecycle()
+
+	// 未声明阶段与顺序的钩子按注册顺序依次启动，上下文在启动完成后仍然有效
+	var order []string
+	var hookCtx context.Context
+	for _, name := range []string{"a", "b", "c"} {
+		l.Append(core.Hook{
+			OnStart: func(ctx context.Context) error {
+				time.Sleep(time.Millisecond)
+				order = append(order, name)
+				hookCtx = ctx
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				order = append(order, "stop "+name)
+				return nil
+			},
+		})
+	}
+
+	if err := l.Start(context.Background(), nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := hookCtx.Err(); err != nil {
+		t.Errorf("Expected start context to stay alive after startup, got %v", err)
+	}
+	_ = l.Stop(context.Background())
+
+	want := []string{"a", "b", "c", "stop c", "stop b", "stop a"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected %v, got %v", want, order)
+	}
+}
+
+func TestLifecycleInvalidOrdering(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+
+	cycle := core.NewLifecycle()
+	cycle.OnStart(noop, core.WithHookName("a"), core.After("b"))
+	cycle.OnStart(noop, core.WithHookName("b"), core.After("a"))
+	if err := cycle.Start(context.Background(), nil); err == nil {
+		t.Error("Expected cycle error, got nil")
+	}
+
+	unknown := core.NewLifecycle()
+	unknown.OnStart(noop, core.WithHookName("a"), core.After("missing"))
+	if err := unknown.Start(context.Background(), nil); err == nil {
+		t.Error("Expected unknown hook error, got nil")
+	}
+
+	conflict := core.NewLifecycle()
+	conflict.OnStart(noop, core.WithHookName("db"), core.InStage(core.StageInfrastructure))
+	conflict.OnStart(noop, core.WithHookName("web"), core.InStage(core.StageHosts), core.Before("db"))
+	if err := conflict.Start(context.Background(), nil); err == nil {
+		t.Error("Expected stage conflict error, got nil")
+	}
+}
+
+func TestLifecycleRollbackErrors(t *testing.T) {
+	l := core.NewLifecycle()
+
+	startErr := errors.New("start failed")
+	stopErr := errors.New("stop failed")
+
+	l.Append(core.Hook{
+		Stage:   core.StageInfrastructure,
+		OnStart: func(context.Context) error { return nil },
+		OnStop:  func(context.Context) error { return stopErr },
+	})
+	l.OnStart(func(context.Context) error { return startErr })
+
+	err := l.Start(context.Background(), nil)
+	if !errors.Is(err, startErr) {
+		t.Errorf("Expected error to wrap start error, got %v", err)
+	}
+	if !errors.Is(err, stopErr) {
+		t.Errorf("Expected error to wrap rollback error, got %v", err)
+	}
+}
+
+func TestLifecycleStopReport(t *testing.T) {
+	rt := core.NewRuntime()
+
+	var report core.ShutdownReport
+	rt.ShutdownReporter = func(r core.ShutdownReport) {
+		report = r
+	}
+
+	stopErr := errors.New("close failed")
+	rt.Lifecycle.OnStop(func(context.Context) error { return nil }, core.WithHookName("fast"))
+	rt.Lifecycle.OnStop(func(context.Context) error { return stopErr }, core.WithHookName("broken"), core.After("fast"))
+	rt.Lifecycle.OnStop(func(ctx context.Context) error {
+		// 模拟不响应取消的钩子
+		time.Sleep(time.Second)
+		return nil
+	}, core.WithHookName("hung"), core.After("broken"), core.WithStopTimeout(20*time.Millisecond))
+
+	if err := rt.Lifecycle.Start(context.Background(), nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	begin := time.Now()
+	err := rt.Lifecycle.Stop(context.Background())
+	if time.Since(begin) > 500*time.Millisecond {
+		t.Errorf("Stop should not wait for the hung hook, took %v", time.Since(begin))
+	}
+	if !errors.Is(err, stopErr) {
+		t.Errorf("Expected joined error to contain hook error, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected joined error to contain timeout, got %v", err)
+	}
+
+	expected := []struct {
+		name    string
+		outcome core.HookOutcome
+	}{
+		{"hung", core.OutcomeTimeout},
+		{"broken", core.OutcomeFailed},
+		{"fast", core.OutcomeOK},
+	}
+	if len(report.Hooks) != len(expected) {
+		t.Fatalf("Expected %d hook reports, got %d", len(expected), len(report.Hooks))
+	}
+	for i, e := range expected {
+		if report.Hooks[i].Name != e.name || report.Hooks[i].Outcome != e.outcome {
+			t.Errorf("Hook %d: expected %s/%s, got %s/%s", i, e.name, e.outcome, report.Hooks[i].Name, report.Hooks[i].Outcome)
+		}
+	}
+}
+
+type closeTracker struct{ closed bool }
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestLifecycleStartFailureClosesContainer(t *testing.T) {
+	rt := core.NewRuntime()
+	tracker := &closeTracker{}
+	if err := rt.Provide(func() *closeTracker { return tracker }); err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+	rt.Lifecycle.OnStart(func(context.Context) error { return errors.New("boom") })
+	if err := rt.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if err := rt.Lifecycle.Start(context.Background(), rt.Container); err == nil {
+		t.Fatal("Expected start error, got nil")
+	}
+	if !tracker.closed {
+		t.Error("Expected the container to be closed after a failed start")
+	}
+}
+
+func TestLifecycleStopWithoutReporterIsQuiet(t *testing.T) {
+	rt := core.NewRuntime()
+	var reported []error
+	rt.ErrorHandler = func(err error) { reported = append(reported, err) }
+	rt.Lifecycle.OnStop(func(context.Context) error { return nil }, core.WithHookName("ok"))
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	if err := rt.Lifecycle.Start(context.Background(), nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	stopErr := rt.Lifecycle.Stop(context.Background())
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	if stopErr != nil {
+		t.Fatalf("Stop failed: %v", stopErr)
+	}
+	if len(out) != 0 || len(reported) != 0 {
+		t.Errorf("Expected a successful shutdown to produce no output, got %q and %v", out, reported)
+	}
+}
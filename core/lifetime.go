@@ -0,0 +1,58 @@
+package core
+
+import "sync"
+
+// ApplicationLifetime 应用生命周期通知
+// 框架会自动将其注册到 DI 容器中，服务可以通过注入获取，
+// 用于在应用退出时提前结束长时间运行的工作，或在排空期间拒绝新的工作。
+type ApplicationLifetime interface {
+	// Started 返回一个在所有启动钩子执行成功后关闭的通道
+	Started() <-chan struct{}
+	// Stopping 返回一个在应用开始停止时关闭的通道，此时停止钩子尚未执行
+	Stopping() <-chan struct{}
+	// Stopped 返回一个在所有停止钩子执行完毕后关闭的通道
+	Stopped() <-chan struct{}
+	// StopApplication 请求应用退出，等价于 Runtime.Shutdown
+	StopApplication()
+}
+
+// applicationLifetime 是 ApplicationLifetime 的默认实现
+type applicationLifetime struct {
+	started  signal
+	stopping signal
+	stopped  signal
+
+	stop func()
+}
+
+func newApplicationLifetime(stop func()) *applicationLifetime {
+	return &applicationLifetime{
+		started:  newSignal(),
+		stopping: newSignal(),
+		stopped:  newSignal(),
+		stop:     stop,
+	}
+}
+
+func (l *applicationLifetime) Started() <-chan struct{}  { return l.started.ch }
+func (l *applicationLifetime) Stopping() <-chan struct{} { return l.stopping.ch }
+func (l *applicationLifetime) Stopped() <-chan struct{}  { return l.stopped.ch }
+
+func (l *applicationLifetime) StopApplication() {
+	l.stop()
+}
+
+// signal 是一个只能触发一次的通知
+type signal struct {
+	ch   chan struct{}
+	once *sync.Once
+}
+
+func newSignal() signal {
+	return signal{ch: make(chan struct{}), once: &sync.Once{}}
+}
+
+// fire 关闭通道，可重复调用
+func (s signal) fire() {
+	s.once.Do(func() { close(s.ch) })
+}
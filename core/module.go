@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Module 具名模块
+// 与普通 Option 不同，模块具有名称和依赖声明：
+//   - 同一个模块实例重复注册会被忽略，只配置一次；注册同名的不同模块实例会导致应用构建失败，
+//     以免后注册的配置（例如不同的选项）被静默丢弃；
+//   - 模块在其依赖的模块配置完成后才会配置，与 Option 的书写顺序无关；
+//   - 依赖的模块缺失或存在循环依赖时，应用构建失败。
+type Module interface {
+	// Name 模块名称，例如 "web"
+	Name() string
+	// DependsOn 依赖的模块名称
+	DependsOn() []string
+	// Configure 配置模块，等价于 Option
+	Configure(rt *Runtime) error
+}
+
+// NewModule 使用配置函数创建模块
+func NewModule(name string, configure Option, dependsOn ...string) Module {
+	return &funcModule{name: name, configure: configure, dependsOn: dependsOn}
+}
+
+// funcModule 基于函数的模块实现
+type funcModule struct {
+	name      string
+	configure Option
+	dependsOn []string
+}
+
+func (m *funcModule) Name() string {
+	return m.name
+}
+
+func (m *funcModule) DependsOn() []string {
+	return m.dependsOn
+}
+
+func (m *funcModule) Configure(rt *Runtime) error {
+	return m.configure(rt)
+}
+
+// WithModule 注册模块
+// 依赖已满足的模块立即配置，否则等待依赖的模块注册后再配置。
+func WithModule(m Module) Option {
+	return func(rt *Runtime) error {
+		return rt.modules.add(rt, m)
+	}
+}
+
+// HasModule 判断模块是否已经配置
+func (rt *Runtime) HasModule(name string) bool {
+	return slices.Contains(rt.modules.configured, name)
+}
+
+// moduleSet 记录模块的配置状态
+type moduleSet struct {
+	configured []string          // 按配置顺序排列的已配置模块
+	pending    []Module          // 等待依赖的模块，按注册顺序排列
+	registered int               // 已注册（未被去重忽略）的模块数量
+	byName     map[string]Module // 已注册的模块实例
+}
+
+// add 注册模块，并配置所有依赖已满足的模块
+func (s *moduleSet) add(rt *Runtime, m Module) error {
+	if existing, ok := s.byName[m.Name()]; ok {
+		if sameModule(existing, m) {
+			return nil
+		}
+		return fmt.Errorf("core: module %q is already registered by a different instance", m.Name())
+	}
+	if s.byName == nil {
+		s.byName = make(map[string]Module)
+	}
+	s.byName[m.Name()] = m
+	s.pending = append(s.pending, m)
+	s.registered++
+	return s.configureReady(rt)
+}
+
+// sameModule 判断两个模块是否为同一个实例，不可比较的模块类型视为不同
+func sameModule(a, b Module) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta.Comparable() && a == b
+}
+
+// configureReady 按注册顺序配置依赖已满足的模块，直到没有可配置的模块
+func (s *moduleSet) configureReady(rt *Runtime) error {
+	for {
+		i := slices.IndexFunc(s.pending, func(m Module) bool {
+			for _, dep := range m.DependsOn() {
+				if !slices.Contains(s.configured, dep) {
+					return false
+				}
+			}
+			return true
+		})
+		if i < 0 {
+			return nil
+		}
+
+		m := s.pending[i]
+		s.pending = slices.Delete(s.pending, i, i+1)
+		// 先标记为已配置，模块在 Configure 中再次注册自身时会被忽略
+		s.configured = append(s.configured, m.Name())
+		start := time.Now()
+		err := m.Configure(rt)
+		rt.trace.record(SpanModule, m.Name(), start, err)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// verify 检查是否存在未能配置的模块
+func (s *moduleSet) verify() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	registered := func(name string) bool {
+		_, ok := s.byName[name]
+		return ok
+	}
+
+	var names []string
+	for _, m := range s.pending {
+		for _, dep := range m.DependsOn() {
+			if !registered(dep) {
+				return fmt.Errorf("core: module %q requires module %q, which is not registered", m.Name(), dep)
+			}
+		}
+		names = append(names, m.Name())
+	}
+	return fmt.Errorf("core: circular module dependency among %s", strings.Join(names, ", "))
+}
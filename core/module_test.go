@@ -0,0 +1,52 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocrud/app/core"
+)
+
+func TestModules(t *testing.T) {
+	var order []string
+	module := func(name string, deps ...string) core.Option {
+		return core.WithModule(core.NewModule(name, func(rt *core.Runtime) error {
+			order = append(order, name)
+			return nil
+		}, deps...))
+	}
+
+	web := module("web", "logging")
+	rt := core.NewRuntime()
+	err := rt.Apply(
+		web,
+		module("cron", "logging", "config"),
+		web, // 同一实例重复注册被忽略
+		module("logging", "config"),
+		module("config"),
+	)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "config,logging,web,cron" {
+		t.Errorf("Unexpected configure order %s", got)
+	}
+	if !rt.HasModule("web") {
+		t.Error("Expected web module to be configured")
+	}
+
+	err = core.NewRuntime().Apply(module("cron", "logging"))
+	if err == nil || !strings.Contains(err.Error(), `requires module "logging"`) {
+		t.Errorf("Expected missing dependency error, got %v", err)
+	}
+
+	err = core.NewRuntime().Apply(module("web"), module("web"))
+	if err == nil || !strings.Contains(err.Error(), "different instance") {
+		t.Errorf("Expected duplicate module error, got %v", err)
+	}
+
+	err = core.NewRuntime().Apply(module("a", "b"), module("b", "a"))
+	if err == nil || !strings.Contains(err.Error(), "circular") {
+		t.Errorf("Expected circular dependency error, got %v", err)
+	}
+}
@@ -2,8 +2,12 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
 	"reflect"
+	"runtime"
+	"time"
 
 	"github.com/gocrud/app/di"
 )
@@ -11,8 +15,12 @@ import (
 // WithHostedService 注册一个托管服务
 // 服务必须实现 HostedService 接口。
 // 框架会在 OnStart 时启动 Goroutine 调用 Start，在 OnStop 时调用 Stop。
-func WithHostedService(constructor any) Option {
+// 如果服务实现了 ReadyNotifier，启动钩子会等待服务就绪后才返回。
+// Start 返回后默认触发应用退出，可通过 WithRestartPolicy 设置重启策略。
+func WithHostedService(constructor any, opts ...HostedOption) Option {
 	return func(rt *Runtime) error {
+		options := newHostedOptions(opts)
+
 		// 1. 注册服务
 		serviceType, err := di.Provide(rt.Container, constructor)
 		if err != nil {
@@ -25,47 +33,109 @@ func WithHostedService(constructor any) Option {
 			return fmt.Errorf("WithHostedService: service %v does not implement core.HostedService", serviceType)
 		}
 
-		var serviceCtx context.Context
-		var serviceCancel context.CancelFunc
+		// 3. 注册生命周期 (启动与停止成对注册，启动失败时可回滚)
+		rt.Lifecycle.Append(hostedServiceHook(rt, di.ServiceKey{Type: serviceType}, options))
+
+		return nil
+	}
+}
+
+// hostedServiceHook 为容器中的托管服务生成成对的启动/停止钩子
+func hostedServiceHook(rt *Runtime, key di.ServiceKey, options *hostedOptions) Hook {
+	name := options.name
+	if name == "" {
+		name = key.Type.String()
+		if key.Name != "" {
+			name = fmt.Sprintf("%s(name=%s)", name, key.Name)
+		}
+	}
+
+	var serviceCtx context.Context
+	var serviceCancel context.CancelFunc
 
-		// 3. 注册生命周期
-		rt.Lifecycle.OnStart(func(ctx context.Context) error {
-			val, err := rt.Container.Get(serviceType)
+	return Hook{
+		Name:  name,
+		Stage: StageHosts,
+		OnStart: func(ctx context.Context) error {
+			val, err := rt.Container.GetNamed(key.Type, key.Name)
 			if err != nil {
-				return fmt.Errorf("failed to resolve hosted service %v: %w", serviceType, err)
+				return fmt.Errorf("failed to resolve hosted service %s: %w", name, err)
 			}
 
 			// 创建服务上下文，生命周期伴随应用运行
 			serviceCtx, serviceCancel = context.WithCancel(context.Background())
 
 			// 异步调用 Start，允许 Start 方法阻塞
-			go func() {
-				if err := val.(HostedService).Start(serviceCtx); err != nil {
-					// 记录错误
-					if rt.ErrorHandler != nil {
-						rt.ErrorHandler(fmt.Errorf("HostedService %v exited with error: %w", serviceType, err))
-					}
-					// 触发应用退出 (Fail Fast)
-					rt.Shutdown()
+			svc := val.(HostedService)
+			sup := newSupervisor(rt, "HostedService "+name, options.restart, svc.Start)
+			exited := sup.start(serviceCtx)
+
+			// 等待服务就绪
+			if notifier, ok := svc.(ReadyNotifier); ok {
+				if err := waitReady(ctx, notifier, exited, options.readyTimeout); err != nil {
+					// 未就绪的服务不会进入停止流程，在此直接清理
+					serviceCancel()
+					stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), options.readyTimeout)
+					_ = svc.Stop(stopCtx)
+					cancel()
+					return fmt.Errorf("hosted service %s failed to become ready: %w", name, err)
 				}
-			}()
-			return nil
-		})
+			}
 
-		rt.Lifecycle.OnStop(func(ctx context.Context) error {
+			// 监控服务退出：按重启策略重启，或触发应用退出 (Fail Fast)
+			go sup.watch(serviceCtx, exited)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
 			// 通知 Context 取消
 			if serviceCancel != nil {
 				serviceCancel()
 			}
 
-			val, err := rt.Container.Get(serviceType)
+			val, err := rt.Container.GetNamed(key.Type, key.Name)
 			if err != nil {
 				return nil
 			}
 			return val.(HostedService).Stop(ctx)
-		})
+		},
+	}
+}
+
+// discoverHostedServices 为容器中标记为托管服务 (di.AsHosted) 的注册生成生命周期钩子
+// 发现的服务使用默认运行选项，需要自定义就绪超时或重启策略时请使用 WithHostedService。
+func (rt *Runtime) discoverHostedServices(container di.Container) ([]Hook, error) {
+	hostedServiceType := reflect.TypeOf((*HostedService)(nil)).Elem()
+
+	var hooks []Hook
+	for _, key := range container.Tagged(di.TagHosted) {
+		if !key.Type.Implements(hostedServiceType) {
+			return nil, fmt.Errorf("lifecycle: service %v is tagged as hosted but does not implement core.HostedService", key.Type)
+		}
+		hooks = append(hooks, hostedServiceHook(rt, key, newHostedOptions(nil)))
+	}
+	return hooks, nil
+}
+
+// waitReady 等待服务就绪、提前退出或超时，timeout 小于等于 0 时使用默认超时时间
+func waitReady(ctx context.Context, notifier ReadyNotifier, exited <-chan error, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultReadyTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 
+	select {
+	case <-notifier.Ready():
 		return nil
+	case err := <-exited:
+		if err == nil {
+			err = errors.New("service exited before becoming ready")
+		}
+		return err
+	case <-timer.C:
+		return fmt.Errorf("not ready within %v", timeout)
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -75,33 +145,46 @@ type WorkerFunc func(ctx context.Context) error
 
 // WithWorker 将一个阻塞的函数注册为后台服务
 // 框架会自动将其适配为 HostedService (异步启动，Cancel停止)
-func WithWorker(fn WorkerFunc) Option {
+// 可通过 WithRestartPolicy 设置函数退出后的重启策略；钩子名称默认为 "worker(函数名)"，可通过 WithHostedName 设置。
+func WithWorker(fn WorkerFunc, opts ...HostedOption) Option {
 	return func(rt *Runtime) error {
+		options := newHostedOptions(opts)
+		name := options.name
+		if name == "" {
+			name = workerName(fn)
+		}
+
 		var workerCtx context.Context
 		var workerCancel context.CancelFunc
 
-		rt.Lifecycle.OnStart(func(ctx context.Context) error {
-			// 使用 Background 确保 Worker 存活
-			workerCtx, workerCancel = context.WithCancel(context.Background())
+		rt.Lifecycle.Append(Hook{
+			Name:  name,
+			Stage: StageHosts,
+			OnStart: func(ctx context.Context) error {
+				// 使用 Background 确保 Worker 存活
+				workerCtx, workerCancel = context.WithCancel(context.Background())
 
-			go func() {
-				if err := fn(workerCtx); err != nil {
-					if rt.ErrorHandler != nil {
-						rt.ErrorHandler(fmt.Errorf("Worker exited with error: %w", err))
-					}
-					rt.Shutdown()
+				sup := newSupervisor(rt, "Worker "+name, options.restart, fn)
+				go sup.watch(workerCtx, sup.start(workerCtx))
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				if workerCancel != nil {
+					workerCancel()
 				}
-			}()
-			return nil
-		})
-
-		rt.Lifecycle.OnStop(func(ctx context.Context) error {
-			if workerCancel != nil {
-				workerCancel()
-			}
-			return nil
+				return nil
+			},
 		})
 
 		return nil
 	}
 }
+
+// workerName 根据函数名称生成 Worker 的默认钩子名称，例如 "worker(main.pollQueue)"
+func workerName(fn WorkerFunc) string {
+	name := "anonymous"
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		name = path.Base(f.Name())
+	}
+	return fmt.Sprintf("worker(%s)", name)
+}
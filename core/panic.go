@@ -0,0 +1,41 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError 表示后台服务发生了 panic
+// 框架在托管服务与 Worker 的 Goroutine 中捕获 panic 并转换为该错误，
+// 随后按照重启策略重启服务，或触发应用的优雅关闭。
+type PanicError struct {
+	// Service 发生 panic 的服务，例如 "HostedService *worker.CleanupWorker"
+	Service string
+	// Value 传给 panic 的原始值
+	Value any
+	// Stack 发生 panic 时的调用栈
+	Stack []byte
+}
+
+// Error 返回包含调用栈的崩溃报告
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v\n\n%s", e.Service, e.Value, e.Stack)
+}
+
+// Unwrap 当 panic 的值本身是 error 时返回该错误
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// safeRun 执行 fn，并将其中的 panic 转换为 PanicError
+func safeRun(service string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Service: service, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,240 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gocrud/app/di"
+)
+
+// BackgroundTask 后台任务
+// scope 是为该任务创建的独立 DI 作用域，任务结束后会被释放；
+// ctx 在应用停止超时、任务被放弃时取消。
+type BackgroundTask func(ctx context.Context, scope di.Scope) error
+
+// BackgroundQueue 后台任务队列
+// 用于在请求处理中提交无需等待结果的工作（例如发送邮件），
+// 任务由固定数量的工作协程执行，应用停止时会在停止超时内处理完队列中的任务。
+type BackgroundQueue interface {
+	// Enqueue 提交任务，name 用于错误报告
+	// 队列已满时阻塞，直到有空位或 ctx 取消；应用开始停止后返回 ErrQueueClosed。
+	Enqueue(ctx context.Context, name string, task BackgroundTask) error
+}
+
+// ErrQueueClosed 后台任务队列已停止接收任务
+var ErrQueueClosed = errors.New("core: background queue is closed")
+
+// QueueOption 配置后台任务队列
+type QueueOption func(*queueOptions)
+
+type queueOptions struct {
+	capacity int
+	workers  int
+}
+
+// WithQueueCapacity 设置队列容量（默认 1024）
+func WithQueueCapacity(n int) QueueOption {
+	return func(o *queueOptions) {
+		o.capacity = n
+	}
+}
+
+// WithQueueWorkers 设置工作协程数量（默认 4）
+func WithQueueWorkers(n int) QueueOption {
+	return func(o *queueOptions) {
+		o.workers = n
+	}
+}
+
+// WithBackgroundQueue 启用后台任务队列，并将 BackgroundQueue 注册到 DI 容器
+// 队列在 StageServices 阶段启动，停止时先于数据库等基础设施、晚于 Web 等主机停止，
+// 因此主机排空期间提交的任务仍会被执行。未能在停止超时内完成的任务会作为停止错误报告。
+func WithBackgroundQueue(opts ...QueueOption) Option {
+	return WithModule(NewModule("background-queue", func(rt *Runtime) error {
+		options := &queueOptions{capacity: 1024, workers: 4}
+		for _, opt := range opts {
+			opt(options)
+		}
+		if options.workers <= 0 || options.capacity < 0 {
+			return fmt.Errorf("core: invalid background queue options: workers=%d capacity=%d", options.workers, options.capacity)
+		}
+
+		q := newBackgroundQueue(rt, options)
+		di.ProvideService[BackgroundQueue](rt.Container, di.WithValue(BackgroundQueue(q)))
+
+		rt.Lifecycle.Append(Hook{
+			Name:    "background-queue",
+			Stage:   StageServices,
+			OnStart: q.start,
+			OnStop:  q.stop,
+		})
+		return nil
+	}))
+}
+
+// queuedTask 已提交的任务
+type queuedTask struct {
+	id   uint64
+	name string
+	run  BackgroundTask
+}
+
+// backgroundQueue BackgroundQueue 的实现
+type backgroundQueue struct {
+	rt      *Runtime
+	workers int
+	tasks   chan queuedTask
+
+	mu          sync.Mutex
+	closed      bool
+	nextID      uint64
+	outstanding map[uint64]string // 尚未完成（排队中或执行中）的任务
+	pending     sync.WaitGroup
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	abandoned atomic.Bool
+	done      chan struct{}
+}
+
+func newBackgroundQueue(rt *Runtime, options *queueOptions) *backgroundQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundQueue{
+		rt:          rt,
+		workers:     options.workers,
+		tasks:       make(chan queuedTask, options.capacity),
+		outstanding: make(map[uint64]string),
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+}
+
+// Enqueue 实现 BackgroundQueue
+func (q *backgroundQueue) Enqueue(ctx context.Context, name string, task BackgroundTask) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrQueueClosed
+	}
+	q.nextID++
+	t := queuedTask{id: q.nextID, name: name, run: task}
+	q.outstanding[t.id] = name
+	q.pending.Add(1)
+	q.mu.Unlock()
+
+	select {
+	case q.tasks <- t:
+		return nil
+	case <-ctx.Done():
+		q.finish(t)
+		return ctx.Err()
+	case <-q.done:
+		q.finish(t)
+		return ErrQueueClosed
+	}
+}
+
+// start 启动工作协程
+func (q *backgroundQueue) start(ctx context.Context) error {
+	for range q.workers {
+		go func() {
+			for {
+				select {
+				case t := <-q.tasks:
+					q.execute(t)
+				case <-q.done:
+					return
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// execute 在独立的作用域中执行任务，放弃后不再执行剩余任务
+func (q *backgroundQueue) execute(t queuedTask) {
+	defer q.finish(t)
+	if q.abandoned.Load() {
+		return
+	}
+
+	scope := q.rt.Container.CreateScope()
+	defer func() {
+		// 队列上下文在放弃时被取消，释放作用域实例不继承其取消信号
+		if err := scope.Dispose(context.WithoutCancel(q.ctx)); err != nil && q.rt.ErrorHandler != nil {
+			q.rt.ErrorHandler(fmt.Errorf("core: background task %s dispose scope: %w", t.name, err))
+		}
+	}()
+
+	if err := safeRun("background task "+t.name, func() error { return t.run(q.ctx, scope) }); err != nil && q.rt.ErrorHandler != nil {
+		q.rt.ErrorHandler(fmt.Errorf("core: background task %s failed: %w", t.name, err))
+	}
+}
+
+// finish 标记任务完成
+func (q *backgroundQueue) finish(t queuedTask) {
+	q.mu.Lock()
+	delete(q.outstanding, t.id)
+	q.mu.Unlock()
+	q.pending.Done()
+}
+
+// stop 停止接收任务并等待队列排空，超时后放弃剩余任务并返回被放弃的任务列表
+func (q *backgroundQueue) stop(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		close(q.done)
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
+	// 超时：取消执行中任务的上下文，剩余任务不再执行
+	q.abandoned.Store(true)
+	q.cancel()
+
+	q.mu.Lock()
+	ids := slices.Sorted(maps.Keys(q.outstanding))
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, q.outstanding[id])
+	}
+	q.mu.Unlock()
+	close(q.done)
+
+	// 丢弃仍在队列中的任务
+	for {
+		select {
+		case t := <-q.tasks:
+			q.finish(t)
+			continue
+		default:
+		}
+		break
+	}
+
+	// 生命周期在同一截止时间上把本钩子记为超时，返回值可能不会出现在关闭报告中，
+	// 因此同时通过 ErrorHandler 报告被放弃的任务
+	err := fmt.Errorf("core: background queue abandoned %d task(s) [%s]: %w", len(names), strings.Join(names, ", "), ctx.Err())
+	if q.rt.ErrorHandler != nil {
+		q.rt.ErrorHandler(err)
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// OnReload 注册重新加载钩子
+// 重新加载按阶段顺序依次执行（配置等基础设施最先），同一阶段内按注册顺序执行。
+func (l *LifecycleEvents) OnReload(fn func(context.Context) error, opts ...HookOption) {
+	hook := Hook{OnReload: fn}
+	for _, opt := range opts {
+		opt(&hook)
+	}
+	l.Append(hook)
+}
+
+// Reload 依次执行已启动钩子的 OnReload
+// 某个钩子失败不会中断其他钩子，返回所有失败钩子错误的组合。
+func (l *LifecycleEvents) Reload(ctx context.Context) error {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+
+	l.mu.Lock()
+	hooks := l.hooks
+	started := slices.Clone(l.started)
+	l.mu.Unlock()
+
+	// 按阶段排序，同一阶段内保持注册顺序
+	slices.SortStableFunc(started, func(a, b int) int {
+		if sa, sb := hooks[a].stage(), hooks[b].stage(); sa != sb {
+			return int(sa - sb)
+		}
+		return a - b
+	})
+
+	var errs []error
+	for _, i := range started {
+		hook := hooks[i]
+		if hook.OnReload == nil {
+			continue
+		}
+		name := hookName(hook, i)
+		if err := safeRun("reload hook "+name, func() error { return hook.OnReload(ctx) }); err != nil {
+			errs = append(errs, fmt.Errorf("lifecycle: reload hook %s failed: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reload 请求重新加载配置及依赖配置的组件
+// 失败会通过 ErrorHandler 报告，但不会导致应用退出。
+func (rt *Runtime) Reload() error {
+	err := rt.Lifecycle.Reload(context.Background())
+	if err != nil && rt.ErrorHandler != nil {
+		rt.ErrorHandler(err)
+	}
+	return err
+}
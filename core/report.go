@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HookOutcome 描述单个停止钩子的执行结果
+type HookOutcome string
+
+const (
+	// OutcomeOK 钩子正常完成
+	OutcomeOK HookOutcome = "ok"
+	// OutcomeFailed 钩子返回了错误
+	OutcomeFailed HookOutcome = "failed"
+	// OutcomeTimeout 钩子未在截止时间内完成
+	OutcomeTimeout HookOutcome = "timeout"
+)
+
+// HookReport 单个停止钩子的执行记录
+type HookReport struct {
+	Name     string
+	Duration time.Duration
+	Outcome  HookOutcome
+	Err      error
+}
+
+// ShutdownReport 一次停止流程的结构化报告
+// 钩子按实际执行顺序排列（即启动的相反顺序）。
+type ShutdownReport struct {
+	Hooks    []HookReport
+	Duration time.Duration
+}
+
+// Failed 报告中是否存在失败或超时的钩子
+func (r ShutdownReport) Failed() bool {
+	for _, h := range r.Hooks {
+		if h.Outcome != OutcomeOK {
+			return true
+		}
+	}
+	return false
+}
+
+// Err 返回所有失败钩子错误的组合，全部成功时返回 nil
+func (r ShutdownReport) Err() error {
+	var errs []error
+	for _, h := range r.Hooks {
+		if h.Outcome == OutcomeOK {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("lifecycle: stop hook %s %s: %w", h.Name, h.Outcome, h.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// String 以表格形式输出报告，便于直接写入日志
+func (r ShutdownReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "shutdown finished in %v\n", r.Duration.Round(time.Millisecond))
+	for _, h := range r.Hooks {
+		fmt.Fprintf(&sb, "  %-32s %-8s %10v", h.Name, h.Outcome, h.Duration.Round(time.Microsecond))
+		if h.Err != nil {
+			fmt.Fprintf(&sb, "  %v", h.Err)
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/gocrud/app/di"
 )
@@ -20,22 +23,73 @@ type Runtime struct {
 	// shutdownCh 用于通知应用退出
 	shutdownCh chan struct{}
 
+	// shutdownErr 记录触发退出的错误 (例如关键服务崩溃)
+	errMu       sync.Mutex
+	shutdownErr error
+
 	// ErrorHandler 用于记录运行时产生的严重错误
 	// 外部可以通过设置此字段来接管错误日志
 	ErrorHandler func(err error)
+
+	// ShutdownReporter 接收生命周期停止时生成的关闭报告
+	// 未设置时，若报告中存在失败或超时的钩子，则转交给 ErrorHandler，否则不输出
+	ShutdownReporter func(report ShutdownReport)
+
+	// DiagnosticsReporter 接收通过 DumpDiagnostics 生成的诊断快照
+	// 未设置时以文本格式输出到标准错误
+	DiagnosticsReporter func(d Diagnostics)
+
+	// diagnostics 模块通过 AddDiagnostics 注册的诊断信息来源
+	diagMu      sync.Mutex
+	diagnostics []diagnosticsSource
+
+	// modules 已注册的模块，applyDepth 用于识别最外层的 Apply
+	modules    moduleSet
+	applyDepth int
+
+	// trace 启动耗时记录
+	trace *StartupTrace
 }
 
 // NewRuntime 创建一个新的运行时实例
 func NewRuntime() *Runtime {
-	return &Runtime{
-		Container:  di.NewContainer(),
+	trace := newStartupTrace()
+	rt := &Runtime{
+		Container:  di.NewContainer(di.WithBuildObserver(trace.observeBuild)),
 		Lifecycle:  NewLifecycle(),
 		shutdownCh: make(chan struct{}),
+		trace:      trace,
 		ErrorHandler: func(err error) {
 			// 默认输出到标准输出
 			fmt.Printf("[Runtime Error] %v\n", err)
 		},
 	}
+	rt.Lifecycle.reporter = rt.reportShutdown
+	rt.Lifecycle.discover = rt.discoverHostedServices
+	rt.Lifecycle.dispose = func(ctx context.Context) error { return rt.Container.Close(ctx) }
+	rt.Lifecycle.trace = trace
+
+	// 注册运行环境，模块可通过 EnvironmentFrom 或注入 *Environment 获取
+	env := NewEnvironment()
+	rt.Features.Set(env)
+	di.ProvideService[*Environment](rt.Container, di.WithValue(env))
+
+	// 注册应用生命周期通知，服务可通过注入 ApplicationLifetime 获取
+	rt.Lifecycle.lifetime = newApplicationLifetime(rt.Shutdown)
+	di.ProvideService[ApplicationLifetime](rt.Container, di.WithValue(rt.Lifecycle.lifetime))
+	return rt
+}
+
+// reportShutdown 分发关闭报告
+func (rt *Runtime) reportShutdown(report ShutdownReport) {
+	if rt.ShutdownReporter != nil {
+		rt.ShutdownReporter(report)
+		return
+	}
+	// 未设置时只报告失败，成功的关闭不产生输出
+	if report.Failed() && rt.ErrorHandler != nil {
+		rt.ErrorHandler(fmt.Errorf("lifecycle: shutdown finished with errors\n%s", report))
+	}
 }
 
 // Shutdown 请求应用退出
@@ -49,6 +103,25 @@ func (rt *Runtime) Shutdown() {
 	}
 }
 
+// ShutdownWithError 记录导致退出的错误并请求应用退出
+// 只保留第一个错误，可通过 Err 获取
+func (rt *Runtime) ShutdownWithError(err error) {
+	rt.errMu.Lock()
+	if rt.shutdownErr == nil {
+		rt.shutdownErr = err
+	}
+	rt.errMu.Unlock()
+	rt.Shutdown()
+}
+
+// Err 返回通过 ShutdownWithError 记录的退出原因
+// 正常退出（信号或 Shutdown）时返回 nil
+func (rt *Runtime) Err() error {
+	rt.errMu.Lock()
+	defer rt.errMu.Unlock()
+	return rt.shutdownErr
+}
+
 // Done 返回一个通道，当应用需要退出时该通道会关闭
 func (rt *Runtime) Done() <-chan struct{} {
 	return rt.shutdownCh
@@ -67,12 +140,25 @@ func (rt *Runtime) Invoke(function any) error {
 }
 
 // Apply 应用多个 Option
+// 最外层的 Apply 结束时，若仍有模块因依赖缺失或循环依赖未能配置，返回错误。
 func (rt *Runtime) Apply(opts ...Option) error {
+	rt.applyDepth++
+	defer func() { rt.applyDepth-- }()
+
 	for _, opt := range opts {
-		if err := opt(rt); err != nil {
+		// 只记录最外层的 Option，注册模块的 Option 由模块自身记录
+		registered, start := rt.modules.registered, time.Now()
+		err := opt(rt)
+		if rt.applyDepth == 1 && rt.modules.registered == registered {
+			rt.trace.record(SpanOption, optionName(opt), start, err)
+		}
+		if err != nil {
 			return err
 		}
 	}
+	if rt.applyDepth == 1 {
+		return rt.modules.verify()
+	}
 	return nil
 }
 
@@ -81,3 +167,9 @@ func (rt *Runtime) Apply(opts ...Option) error {
 func As[T any]() di.Option {
 	return di.Use[T]()
 }
+
+// AsHosted 是一个辅助函数，用于生成 di.Option，将服务标记为托管服务
+// 标记后的服务无需调用 WithHostedService，会在应用启动时被自动发现并启动
+func AsHosted() di.Option {
+	return di.AsHosted()
+}
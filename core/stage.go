@@ -0,0 +1,195 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Stage 生命周期阶段
+// 启动时按阶段从小到大依次执行，同一阶段内的钩子并发启动（未声明阶段与顺序的钩子按注册顺序依次启动）；停止时顺序相反。
+type Stage int
+
+const (
+	// StageInfrastructure 基础设施阶段：配置、数据库、缓存、注册中心等
+	StageInfrastructure Stage = iota + 1
+	// StageServices 业务服务阶段（未指定阶段的钩子默认位于此阶段）
+	StageServices
+	// StageHosts 宿主阶段：Web 服务、后台 Worker、定时任务等对外提供服务的组件
+	StageHosts
+)
+
+// String 返回阶段名称
+func (s Stage) String() string {
+	switch s {
+	case StageInfrastructure:
+		return "infrastructure"
+	case StageServices:
+		return "services"
+	case StageHosts:
+		return "hosts"
+	default:
+		return fmt.Sprintf("stage(%d)", int(s))
+	}
+}
+
+// MarshalText 以阶段名称输出，用于诊断信息的 JSON 格式
+func (s Stage) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// InStage 设置钩子所在的阶段
+func InStage(stage Stage) HookOption {
+	return func(h *Hook) {
+		h.Stage = stage
+	}
+}
+
+// After 声明钩子必须在指定名称的钩子启动完成之后启动
+func After(names ...string) HookOption {
+	return func(h *Hook) {
+		h.After = append(h.After, names...)
+	}
+}
+
+// Before 声明钩子必须在指定名称的钩子启动之前完成启动
+func Before(names ...string) HookOption {
+	return func(h *Hook) {
+		h.Before = append(h.Before, names...)
+	}
+}
+
+// stage 返回钩子的有效阶段，未指定时默认为 StageServices
+func (h Hook) stage() Stage {
+	if h.Stage == 0 {
+		return StageServices
+	}
+	return h.Stage
+}
+
+// startPlan 启动计划
+type startPlan struct {
+	stages [][]int       // 按阶段分组的钩子下标，阶段按顺序排列
+	preds  map[int][]int // 每个钩子在同一阶段内必须等待的前置钩子
+}
+
+// planStart 根据阶段与 After/Before 声明生成启动计划
+// 引用不存在的钩子、跨阶段的逆序声明以及循环依赖都会返回错误。
+func planStart(hooks []Hook) (*startPlan, error) {
+	byName := make(map[string][]int)
+	for i, h := range hooks {
+		if h.Name != "" {
+			byName[h.Name] = append(byName[h.Name], i)
+		}
+	}
+
+	plan := &startPlan{preds: make(map[int][]int)}
+
+	// addEdge 声明 from 必须在 to 之前启动
+	addEdge := func(from, to int) error {
+		fromStage, toStage := hooks[from].stage(), hooks[to].stage()
+		switch {
+		case fromStage < toStage:
+			// 阶段顺序已经保证
+			return nil
+		case fromStage > toStage:
+			return fmt.Errorf("lifecycle: hook %s (%s) cannot start before hook %s (%s)",
+				hookName(hooks[from], from), fromStage, hookName(hooks[to], to), toStage)
+		}
+		plan.preds[to] = append(plan.preds[to], from)
+		return nil
+	}
+
+	// 未声明阶段与顺序的钩子保持注册顺序依次启动，与引入阶段之前的行为一致
+	lastPlain := -1
+	for i, h := range hooks {
+		if h.Stage != 0 || len(h.After) > 0 || len(h.Before) > 0 {
+			continue
+		}
+		if lastPlain >= 0 {
+			plan.preds[i] = append(plan.preds[i], lastPlain)
+		}
+		lastPlain = i
+	}
+
+	for i, h := range hooks {
+		for _, name := range h.After {
+			targets, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("lifecycle: hook %s declares After unknown hook %q", hookName(h, i), name)
+			}
+			for _, t := range targets {
+				if err := addEdge(t, i); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, name := range h.Before {
+			targets, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("lifecycle: hook %s declares Before unknown hook %q", hookName(h, i), name)
+			}
+			for _, t := range targets {
+				if err := addEdge(i, t); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// 按阶段分组
+	groups := make(map[Stage][]int)
+	var stages []Stage
+	for i, h := range hooks {
+		s := h.stage()
+		if _, ok := groups[s]; !ok {
+			stages = append(stages, s)
+		}
+		groups[s] = append(groups[s], i)
+	}
+	sort.Slice(stages, func(a, b int) bool { return stages[a] < stages[b] })
+	for _, s := range stages {
+		plan.stages = append(plan.stages, groups[s])
+	}
+
+	if err := plan.checkCycles(hooks); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// checkCycles 检测阶段内的循环依赖
+func (p *startPlan) checkCycles(hooks []Hook) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int]int)
+
+	var visit func(int) error
+	visit = func(u int) error {
+		state[u] = visiting
+		for _, v := range p.preds[u] {
+			switch state[v] {
+			case visiting:
+				return fmt.Errorf("lifecycle: ordering cycle detected between hook %s and hook %s",
+					hookName(hooks[v], v), hookName(hooks[u], u))
+			case unvisited:
+				if err := visit(v); err != nil {
+					return err
+				}
+			}
+		}
+		state[u] = visited
+		return nil
+	}
+
+	for i := range hooks {
+		if state[i] == unvisited {
+			if err := visit(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
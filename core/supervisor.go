@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RestartMode 托管服务退出后的重启模式
+type RestartMode int
+
+const (
+	// RestartNever 从不重启：返回错误时立即触发应用退出（默认）
+	RestartNever RestartMode = iota
+	// RestartOnFailure 仅在返回错误时重启
+	RestartOnFailure
+	// RestartAlways 无论是否返回错误都重启（应用停止时除外）
+	RestartAlways
+)
+
+// RestartPolicy 托管服务和 Worker 的重启策略
+// 重启间隔按指数退避增长，并叠加随机抖动；
+// 在 Window 时间窗口内重启次数超过 MaxRestarts 时，不再重启并触发应用退出。
+type RestartPolicy struct {
+	Mode RestartMode
+
+	// InitialBackoff 第一次重启前的等待时间，默认 1 秒
+	InitialBackoff time.Duration
+	// MaxBackoff 重启等待时间上限，默认 30 秒
+	MaxBackoff time.Duration
+	// Multiplier 每次重启等待时间的增长倍数，默认 2
+	Multiplier float64
+	// Jitter 随机抖动比例 (0~1)，默认 0.2，即在 ±20% 范围内浮动；小于 0 表示不抖动，大于 1 按 1 处理
+	Jitter float64
+
+	// MaxRestarts 时间窗口内允许的最大重启次数，默认 5；小于 0 表示不限制
+	MaxRestarts int
+	// Window 统计重启次数的滑动时间窗口，默认 1 分钟
+	Window time.Duration
+}
+
+// WithRestartPolicy 设置托管服务或 Worker 的重启策略
+// 重启时会在同一个服务实例上再次调用 Start（Worker 再次调用同一个函数），
+// 因此托管服务必须支持重复启动：不能依赖只能执行一次的初始化，例如关闭已关闭的通道或重复注册资源。
+func WithRestartPolicy(policy RestartPolicy) HostedOption {
+	return func(o *hostedOptions) {
+		o.restart = policy
+	}
+}
+
+// withDefaults 填充未设置的策略参数
+func (p RestartPolicy) withDefaults() RestartPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = time.Second
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 30 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	switch {
+	case p.Jitter < 0:
+		p.Jitter = 0
+	case p.Jitter == 0:
+		p.Jitter = 0.2
+	case p.Jitter > 1:
+		p.Jitter = 1
+	}
+	if p.MaxRestarts == 0 {
+		p.MaxRestarts = 5
+	}
+	if p.Window <= 0 {
+		p.Window = time.Minute
+	}
+	return p
+}
+
+// supervisor 监控后台运行的服务，并按重启策略处理退出
+type supervisor struct {
+	rt     *Runtime
+	name   string
+	policy RestartPolicy
+	run    func(context.Context) error
+
+	attempts int         // 累计重启次数
+	recent   []time.Time // 时间窗口内的重启时间
+}
+
+func newSupervisor(rt *Runtime, name string, policy RestartPolicy, run func(context.Context) error) *supervisor {
+	return &supervisor{
+		rt:     rt,
+		name:   name,
+		policy: policy.withDefaults(),
+		run:    run,
+	}
+}
+
+// start 在独立的 Goroutine 中运行一次服务
+// 服务中的 panic 会被捕获并转换为 PanicError，与普通错误一样交由 watch 处理。
+func (s *supervisor) start(ctx context.Context) <-chan error {
+	exited := make(chan error, 1)
+	go func() {
+		exited <- safeRun(s.name, func() error { return s.run(ctx) })
+	}()
+	return exited
+}
+
+// watch 等待服务退出并决定重启或触发应用退出，直到 ctx 被取消
+func (s *supervisor) watch(ctx context.Context, exited <-chan error) {
+	for {
+		var err error
+		select {
+		case err = <-exited:
+		case <-ctx.Done():
+			return
+		}
+
+		// 应用正在停止，服务退出属于预期行为
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !s.shouldRestart(err) {
+			if err != nil {
+				s.fail(fmt.Errorf("%s exited with error: %w", s.name, err))
+			}
+			return
+		}
+
+		if !s.allowRestart() {
+			budgetErr := fmt.Errorf("%s exceeded restart budget (%d restarts within %v)",
+				s.name, s.policy.MaxRestarts, s.policy.Window)
+			if err != nil {
+				budgetErr = fmt.Errorf("%w: %w", budgetErr, err)
+			}
+			s.fail(budgetErr)
+			return
+		}
+
+		s.attempts++
+		delay := s.backoff()
+		if s.rt.ErrorHandler != nil {
+			if err != nil {
+				s.rt.ErrorHandler(fmt.Errorf("%s exited with error, restarting in %v (attempt %d): %w", s.name, delay, s.attempts, err))
+			} else {
+				s.rt.ErrorHandler(fmt.Errorf("%s exited, restarting in %v (attempt %d)", s.name, delay, s.attempts))
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		exited = s.start(ctx)
+	}
+}
+
+// shouldRestart 根据重启模式判断是否需要重启
+func (s *supervisor) shouldRestart(err error) bool {
+	switch s.policy.Mode {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// allowRestart 检查时间窗口内的重启预算，并记录本次重启
+func (s *supervisor) allowRestart() bool {
+	now := time.Now()
+	kept := s.recent[:0]
+	for _, t := range s.recent {
+		if now.Sub(t) < s.policy.Window {
+			kept = append(kept, t)
+		}
+	}
+	s.recent = kept
+
+	if s.policy.MaxRestarts >= 0 && len(s.recent) >= s.policy.MaxRestarts {
+		return false
+	}
+	s.recent = append(s.recent, now)
+	return true
+}
+
+// backoff 计算下一次重启前的等待时间
+// 退避指数基于时间窗口内的重启次数，服务稳定运行一段时间后等待时间会自动回落。
+func (s *supervisor) backoff() time.Duration {
+	exp := float64(len(s.recent) - 1)
+	delay := float64(s.policy.InitialBackoff) * math.Pow(s.policy.Multiplier, exp)
+	if delay > float64(s.policy.MaxBackoff) {
+		delay = float64(s.policy.MaxBackoff)
+	}
+	if s.policy.Jitter > 0 {
+		delay *= 1 + s.policy.Jitter*(rand.Float64()*2-1)
+	}
+	return time.Duration(delay)
+}
+
+// fail 报告错误并触发应用退出 (Fail Fast)
+func (s *supervisor) fail(err error) {
+	if s.rt.ErrorHandler != nil {
+		s.rt.ErrorHandler(err)
+	}
+	s.rt.ShutdownWithError(err)
+}
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SystemdOption 配置 systemd 集成
+type SystemdOption func(*systemdOptions)
+
+type systemdOptions struct {
+	check func(ctx context.Context) error
+}
+
+// WithWatchdogCheck 设置看门狗检查
+// 每次发送 WATCHDOG=1 前执行，返回错误时跳过本次通知，
+// 持续失败超过 WatchdogSec 后 systemd 会按单元配置重启服务。
+func WithWatchdogCheck(check func(ctx context.Context) error) SystemdOption {
+	return func(o *systemdOptions) {
+		o.check = check
+	}
+}
+
+// WithSystemd 启用 systemd 集成，适用于 Type=notify 的单元
+//   - 所有启动钩子执行成功后发送 READY=1；
+//   - 应用开始停止时发送 STOPPING=1；
+//   - 单元配置了 WatchdogSec 时，以一半的间隔发送 WATCHDOG=1，直到停止完成，检查失败时跳过本次通知。
+//
+// 未通过 systemd 启动（没有 NOTIFY_SOCKET）时不做任何操作。
+func WithSystemd(opts ...SystemdOption) Option {
+	return WithModule(NewModule("systemd", func(rt *Runtime) error {
+		options := &systemdOptions{}
+		for _, opt := range opts {
+			opt(options)
+		}
+
+		socket := os.Getenv("NOTIFY_SOCKET")
+		if socket == "" {
+			return nil
+		}
+		interval, err := watchdogInterval()
+		if err != nil {
+			return err
+		}
+
+		notify := func(state string) {
+			if err := sdNotify(socket, state); err != nil && rt.ErrorHandler != nil {
+				rt.ErrorHandler(err)
+			}
+		}
+
+		// 通知在独立的 Goroutine 中发送；钩子最后停止，排空期间看门狗通知不会中断
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+		rt.Lifecycle.Append(Hook{
+			Name:  "systemd",
+			Stage: StageInfrastructure,
+			OnStart: func(ctx context.Context) error {
+				lifetime := rt.Lifecycle.lifetime
+				wg.Go(func() {
+					select {
+					case <-lifetime.Started():
+						notify("READY=1")
+						<-lifetime.Stopping()
+					case <-lifetime.Stopping():
+					}
+					notify("STOPPING=1")
+				})
+				if interval > 0 {
+					wg.Go(func() {
+						ticker := time.NewTicker(interval)
+						defer ticker.Stop()
+						for {
+							select {
+							case <-ticker.C:
+							case <-done:
+								return
+							}
+							if options.check != nil {
+								checkCtx, cancel := context.WithTimeout(context.Background(), interval)
+								err := safeRun("watchdog check", func() error { return options.check(checkCtx) })
+								cancel()
+								if err != nil {
+									if rt.ErrorHandler != nil {
+										rt.ErrorHandler(fmt.Errorf("core: watchdog check failed: %w", err))
+									}
+									continue
+								}
+							}
+							notify("WATCHDOG=1")
+						}
+					})
+				}
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				close(done)
+				wg.Wait()
+				return nil
+			},
+		})
+		return nil
+	}))
+}
+
+// SystemdNotify 向 systemd 发送状态通知，例如 "READY=1" 或 "STATUS=migrating"
+// 未通过 systemd 启动（没有 NOTIFY_SOCKET）时返回 false。
+func SystemdNotify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	return true, sdNotify(socket, state)
+}
+
+// sdNotify 向通知套接字发送一条消息，以 @ 开头的路径表示抽象命名空间
+func sdNotify(socket, state string) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("core: failed to connect to systemd notify socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("core: failed to notify systemd %q: %w", state, err)
+	}
+	return nil
+}
+
+// watchdogInterval 根据 WATCHDOG_USEC 返回通知间隔，未启用看门狗时返回 0
+func watchdogInterval() (time.Duration, error) {
+	value := os.Getenv("WATCHDOG_USEC")
+	if value == "" {
+		return 0, nil
+	}
+	// WATCHDOG_PID 指定的不是当前进程时，看门狗不属于本进程
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+	usec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, fmt.Errorf("core: invalid WATCHDOG_USEC %q", value)
+	}
+	return time.Duration(usec) * time.Microsecond / 2, nil
+}
+
+// SystemdListener 通过 socket 激活 (LISTEN_FDS) 继承的监听器
+type SystemdListener struct {
+	net.Listener
+	// Name 单元中 FileDescriptorName 配置的名称，未配置时为空
+	Name string
+}
+
+// SystemdListeners 返回通过 socket 激活继承的监听器
+// 继承的文件描述符只会被转换一次，多次调用返回相同的监听器；
+// 未通过 socket 激活启动时返回空列表。
+func SystemdListeners() ([]SystemdListener, error) {
+	return systemdListeners()
+}
+
+// systemdListeners 文件描述符从 3 开始，数量由 LISTEN_FDS 指定，且 LISTEN_PID 必须为当前进程
+var systemdListeners = sync.OnceValues(func() ([]SystemdListener, error) {
+	const listenFdsStart = 3
+
+	if pid := os.Getenv("LISTEN_PID"); pid == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	// 避免子进程再次继承
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]SystemdListener, 0, count)
+	for i := range count {
+		file := os.NewFile(uintptr(listenFdsStart+i), fmt.Sprintf("LISTEN_FD_%d", listenFdsStart+i))
+		ln, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("core: inherited file descriptor %d is not a listener: %w", listenFdsStart+i, err)
+		}
+		var name string
+		if i < len(names) {
+			name = names[i]
+		}
+		listeners = append(listeners, SystemdListener{Listener: ln, Name: name})
+	}
+	return listeners, nil
+})
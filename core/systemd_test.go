@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app/core"
+)
+
+func TestSystemd(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not supported: %v", err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", socket)
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	// 健康检查失败期间不发送 WATCHDOG=1
+	var healthy atomic.Bool
+	rt := core.NewRuntime()
+	rt.ErrorHandler = func(err error) {}
+	err = rt.Apply(core.WithSystemd(core.WithWatchdogCheck(func(ctx context.Context) error {
+		if !healthy.Load() {
+			return errors.New("database unreachable")
+		}
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := rt.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	receive := func() string {
+		buf := make([]byte, 256)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("Expected notification: %v", err)
+		}
+		return string(buf[:n])
+	}
+
+	if err := rt.Lifecycle.Start(context.Background(), rt.Container); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if msg := receive(); msg != "READY=1" {
+		t.Fatalf("Expected READY=1, got %q", msg)
+	}
+
+	healthy.Store(true)
+	if msg := receive(); msg != "WATCHDOG=1" {
+		t.Fatalf("Expected WATCHDOG=1, got %q", msg)
+	}
+
+	if err := rt.Lifecycle.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	for {
+		msg := receive()
+		if msg == "STOPPING=1" {
+			break
+		}
+		if msg != "WATCHDOG=1" {
+			t.Fatalf("Unexpected notification %q", msg)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gocrud/app/di"
+)
+
+// SpanKind 启动耗时记录的类别
+type SpanKind string
+
+const (
+	// SpanOption 应用一个普通 Option
+	SpanOption SpanKind = "option"
+	// SpanModule 配置一个模块
+	SpanModule SpanKind = "module"
+	// SpanBuild 构建 DI 容器（包含所有单例的构建）
+	SpanBuild SpanKind = "build"
+	// SpanService 构建一个单例服务
+	SpanService SpanKind = "service"
+	// SpanHook 执行一个启动钩子
+	SpanHook SpanKind = "hook"
+)
+
+// Span 一段启动耗时记录
+type Span struct {
+	Kind     SpanKind      `json:"kind"`
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Err      error         `json:"-"`
+}
+
+// MarshalJSON 额外输出错误信息，耗时以字符串表示
+func (s Span) MarshalJSON() ([]byte, error) {
+	type span Span
+	var errMsg string
+	if s.Err != nil {
+		errMsg = s.Err.Error()
+	}
+	return json.Marshal(struct {
+		span
+		Duration string `json:"duration"`
+		Error    string `json:"error,omitempty"`
+	}{span(s), s.Duration.String(), errMsg})
+}
+
+// StartupTrace 启动过程的耗时记录
+// 记录每个 Option 与模块的应用、DI 容器构建（每个单例的构建）以及每个启动钩子的执行。
+type StartupTrace struct {
+	mu    sync.Mutex
+	begin time.Time
+	spans []Span
+}
+
+// newStartupTrace 创建启动耗时记录，起点为 Runtime 创建的时间
+func newStartupTrace() *StartupTrace {
+	return &StartupTrace{begin: time.Now()}
+}
+
+// record 记录一段耗时
+func (t *StartupTrace) record(kind SpanKind, name string, start time.Time, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, Span{Kind: kind, Name: name, Start: start, Duration: time.Since(start), Err: err})
+}
+
+// observeBuild 记录 DI 容器构建期间的单例构建
+func (t *StartupTrace) observeBuild(evt di.BuildEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, Span{Kind: SpanService, Name: evt.Key.String(), Start: evt.Start, Duration: evt.Duration, Err: evt.Err})
+}
+
+// Spans 按开始时间返回所有记录
+func (t *StartupTrace) Spans() []Span {
+	t.mu.Lock()
+	spans := slices.Clone(t.spans)
+	t.mu.Unlock()
+
+	slices.SortStableFunc(spans, func(a, b Span) int {
+		return a.Start.Compare(b.Start)
+	})
+	return spans
+}
+
+// Duration 返回从 Runtime 创建到最后一段记录结束的总耗时
+func (t *StartupTrace) Duration() time.Duration {
+	var end time.Time
+	for _, span := range t.Spans() {
+		if spanEnd := span.Start.Add(span.Duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	if end.IsZero() {
+		return 0
+	}
+	return end.Sub(t.begin)
+}
+
+// MarshalJSON 输出总耗时与所有记录
+func (t *StartupTrace) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Start    time.Time `json:"start"`
+		Duration string    `json:"duration"`
+		Spans    []Span    `json:"spans"`
+	}{t.begin, t.Duration().String(), t.Spans()})
+}
+
+// WriteTable 以表格形式输出启动耗时，OFFSET 为相对 Runtime 创建时间的偏移
+func (t *StartupTrace) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tOFFSET\tDURATION\tERROR")
+	for _, span := range t.Spans() {
+		errMsg := ""
+		if span.Err != nil {
+			errMsg = strings.SplitN(span.Err.Error(), "\n", 2)[0]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%v\t%s\n", span.Kind, span.Name,
+			span.Start.Sub(t.begin).Round(time.Microsecond), span.Duration.Round(time.Microsecond), errMsg)
+	}
+	fmt.Fprintf(tw, "total\t\t\t%v\t\n", t.Duration().Round(time.Microsecond))
+	return tw.Flush()
+}
+
+// StartupTrace 返回启动过程的耗时记录
+func (rt *Runtime) StartupTrace() *StartupTrace {
+	return rt.trace
+}
+
+// Build 构建 DI 容器，并记录构建耗时
+func (rt *Runtime) Build() error {
+	start := time.Now()
+	err := rt.Container.Build()
+	rt.trace.record(SpanBuild, "container", start, err)
+	return err
+}
+
+// optionName 返回 Option 函数的名称，例如 "main.main.func1"
+func optionName(opt Option) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(opt).Pointer()); fn != nil {
+		name := fn.Name()
+		// 去掉包路径，只保留最后一段
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		return name
+	}
+	return "option"
+}
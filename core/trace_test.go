@@ -0,0 +1,88 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+type slowClient struct{}
+
+func TestStartupTrace(t *testing.T) {
+	rt := core.NewRuntime()
+	err := rt.Apply(
+		func(rt *core.Runtime) error {
+			return rt.Provide(func() *slowClient {
+				time.Sleep(10 * time.Millisecond)
+				return &slowClient{}
+			})
+		},
+		core.WithModule(core.NewModule("cache", func(rt *core.Runtime) error {
+			rt.Lifecycle.OnStart(func(ctx context.Context) error {
+				return errors.New("connection refused")
+			}, core.WithHookName("cache-connect"))
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := rt.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	_ = rt.Lifecycle.Start(context.Background(), rt.Container)
+
+	spans := map[core.SpanKind][]core.Span{}
+	for _, span := range rt.StartupTrace().Spans() {
+		spans[span.Kind] = append(spans[span.Kind], span)
+	}
+
+	if len(spans[core.SpanOption]) != 1 || len(spans[core.SpanModule]) != 1 || spans[core.SpanModule][0].Name != "cache" {
+		t.Errorf("Expected one option and one module span, got %+v", spans)
+	}
+	if len(spans[core.SpanBuild]) != 1 {
+		t.Errorf("Expected one build span, got %+v", spans[core.SpanBuild])
+	}
+
+	client := di.ServiceKey{Type: reflect.TypeFor[*slowClient]()}.String()
+	var found bool
+	for _, span := range spans[core.SpanService] {
+		if span.Name == client {
+			found = true
+			if span.Duration < 10*time.Millisecond {
+				t.Errorf("Expected service span to include constructor time, got %v", span.Duration)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a span for %s, got %+v", client, spans[core.SpanService])
+	}
+
+	if hooks := spans[core.SpanHook]; len(hooks) != 1 || hooks[0].Name != "cache-connect" || hooks[0].Err == nil {
+		t.Errorf("Expected failed hook span, got %+v", hooks)
+	}
+
+	data, err := json.Marshal(rt.StartupTrace())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"error":"connection refused"`) {
+		t.Errorf("Expected hook error in JSON, got %s", data)
+	}
+
+	var table bytes.Buffer
+	if err := rt.StartupTrace().WriteTable(&table); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	if !strings.Contains(table.String(), "cache-connect") {
+		t.Errorf("Expected hook in table, got\n%s", table.String())
+	}
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 优雅升级时父进程通过环境变量告诉新进程继承的监听器与就绪管道
+const (
+	upgradeListenersEnv = "GOCRUD_UPGRADE_LISTENERS"
+	upgradeReadyEnv     = "GOCRUD_UPGRADE_READY_FD"
+)
+
+// Listen 监听网络地址，用法与 net.Listen 相同
+// 通过 Listen 创建的监听器会在优雅升级 (Upgrade) 时传给新进程；
+// 新进程以相同的 network 与 address 调用 Listen 时直接复用继承的监听器，升级期间不会拒绝连接；
+// 以相同参数监听多个监听器时按监听顺序一一对应，已关闭的监听器不占序号。
+func Listen(network, address string) (net.Listener, error) {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	key := listeners.nextKey(network, address)
+
+	ln, ok := listeners.inherited()[key]
+	if ok {
+		delete(listeners.inherited(), key)
+	} else {
+		var err error
+		if ln, err = net.Listen(network, address); err != nil {
+			return nil, err
+		}
+	}
+
+	tracked := &trackedListener{Listener: ln, key: key}
+	listeners.active = append(listeners.active, tracked)
+	return tracked, nil
+}
+
+// ErrNotSocketActivated 当前进程不是通过 systemd socket 激活启动的
+var ErrNotSocketActivated = errors.New("core: not started by systemd socket activation")
+
+// ListenSystemd 返回通过 socket 激活 (LISTEN_FDS) 继承的监听器，name 为空时使用第一个
+// 与 Listen 一样，返回的监听器会在优雅升级时传给新进程，新进程以相同的 name 调用时直接复用；
+// 未通过 socket 激活启动时返回 ErrNotSocketActivated，调用方可以改用 Listen 监听端口。
+// 每次调用返回继承监听器的副本，关闭后（例如服务重启）可以再次调用。
+func ListenSystemd(name string) (net.Listener, error) {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	key := listeners.nextKey("systemd", name)
+
+	origin, ok := listeners.systemd[name]
+	if !ok {
+		if origin, ok = listeners.inherited()[key]; ok {
+			delete(listeners.inherited(), key)
+		} else {
+			activated, err := SystemdListeners()
+			if err != nil {
+				return nil, err
+			}
+			if len(activated) == 0 {
+				return nil, ErrNotSocketActivated
+			}
+			for _, sl := range activated {
+				if name == "" || sl.Name == name {
+					origin = sl.Listener
+					break
+				}
+			}
+			if origin == nil {
+				return nil, fmt.Errorf("core: no inherited listener named %q", name)
+			}
+		}
+		if listeners.systemd == nil {
+			listeners.systemd = make(map[string]net.Listener)
+		}
+		listeners.systemd[name] = origin
+	}
+
+	ln, err := dupListener(origin)
+	if err != nil {
+		return nil, fmt.Errorf("core: failed to duplicate inherited listener %q: %w", name, err)
+	}
+	tracked := &trackedListener{Listener: ln, key: key}
+	listeners.active = append(listeners.active, tracked)
+	return tracked, nil
+}
+
+// dupListener 复制监听器的文件描述符，关闭副本不影响原监听器
+func dupListener(ln net.Listener) (net.Listener, error) {
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("%T does not expose its file descriptor", ln)
+	}
+	f, err := filer.File()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return net.FileListener(f)
+}
+
+// Upgrade 启动当前可执行文件的新进程，并将所有通过 Listen 创建的监听器传给它
+// 新进程完成启动 (调用 UpgradeReady) 后返回其进程号，此时调用方应停止接收新工作、排空后退出；
+// 新进程在就绪前退出或 ctx 取消时返回错误，当前进程继续正常运行。
+// 可执行文件在磁盘上被替换后，新进程运行的是新版本。
+func Upgrade(ctx context.Context) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("core: upgrade failed to locate executable: %w", err)
+	}
+
+	keys, files, err := listeners.files()
+	if err != nil {
+		return 0, fmt.Errorf("core: upgrade failed to hand off listeners: %w", err)
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return 0, fmt.Errorf("core: upgrade failed to create ready pipe: %w", err)
+	}
+	defer ready.Close()
+
+	encodedKeys, err := json.Marshal(keys)
+	if err != nil {
+		return 0, fmt.Errorf("core: upgrade failed to encode listeners: %w", err)
+	}
+
+	// ExtraFiles 中的第 i 个文件在新进程中为文件描述符 3+i
+	env := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		return strings.HasPrefix(kv, upgradeListenersEnv+"=") || strings.HasPrefix(kv, upgradeReadyEnv+"=")
+	})
+	env = append(env,
+		upgradeListenersEnv+"="+string(encodedKeys),
+		upgradeReadyEnv+"="+strconv.Itoa(3+len(files)),
+	)
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(slices.Clone(files), readyWriter)
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return 0, fmt.Errorf("core: upgrade failed to start %s: %w", executable, err)
+	}
+	// 回收新进程，避免其退出后成为僵尸进程
+	go func() { _ = cmd.Wait() }()
+
+	// 新进程就绪时写入一个字节；退出时管道关闭，读取返回 EOF
+	result := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		_, err := ready.Read(buf)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errors.New("process exited before becoming ready")
+			}
+			return 0, fmt.Errorf("core: upgrade to pid %d failed: %w", cmd.Process.Pid, err)
+		}
+		return cmd.Process.Pid, nil
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		return 0, fmt.Errorf("core: upgrade to pid %d failed: %w", cmd.Process.Pid, ctx.Err())
+	}
+}
+
+// UpgradeReady 通知父进程当前进程已完成启动，父进程随后开始排空并退出
+// 不是由 Upgrade 启动的进程调用时不做任何操作，只有第一次调用生效。
+func UpgradeReady() error {
+	return upgradeReady()
+}
+
+var upgradeReady = sync.OnceValue(func() error {
+	value := os.Getenv(upgradeReadyEnv)
+	if value == "" {
+		return nil
+	}
+	os.Unsetenv(upgradeReadyEnv)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("core: invalid %s %q", upgradeReadyEnv, value)
+	}
+	pipe := os.NewFile(uintptr(fd), "upgrade-ready")
+	defer pipe.Close()
+	if _, err := pipe.Write([]byte{1}); err != nil {
+		return fmt.Errorf("core: failed to notify parent process: %w", err)
+	}
+	return nil
+})
+
+// listeners 进程内所有通过 Listen 创建的监听器
+var listeners listenerRegistry
+
+// listenerRegistry 记录可在升级时传给新进程的监听器
+type listenerRegistry struct {
+	mu         sync.Mutex
+	active     []*trackedListener
+	systemd    map[string]net.Listener // 按名称缓存的 socket 激活监听器，ListenSystemd 返回其副本
+	parsed     bool
+	fromParent map[listenerKey]net.Listener // 从父进程继承、尚未被 Listen 取用的监听器
+}
+
+// nextKey 返回本次调用的监听器标识，调用方需持有锁
+// Index 取相同参数的活跃监听器未使用的最小序号，已关闭的监听器（例如服务重启前的）会让出序号，
+// 因此新旧进程中按相同顺序监听的监听器总能对应。
+func (r *listenerRegistry) nextKey(network, address string) listenerKey {
+	key := listenerKey{Network: network, Address: address}
+	for slices.ContainsFunc(r.active, func(ln *trackedListener) bool { return ln.key == key }) {
+		key.Index++
+	}
+	return key
+}
+
+// inherited 返回从父进程继承的监听器，首次调用时解析环境变量，调用方需持有锁
+func (r *listenerRegistry) inherited() map[listenerKey]net.Listener {
+	if r.parsed {
+		return r.fromParent
+	}
+	r.parsed = true
+	r.fromParent = make(map[listenerKey]net.Listener)
+
+	value := os.Getenv(upgradeListenersEnv)
+	if value == "" {
+		return r.fromParent
+	}
+	os.Unsetenv(upgradeListenersEnv)
+
+	var keys []listenerKey
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		// 无法解析时不复用任何监听器，Listen 会重新监听
+		return r.fromParent
+	}
+	for i, key := range keys {
+		file := os.NewFile(uintptr(3+i), key.String())
+		ln, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			// 无法还原的监听器被忽略，Listen 会重新监听该地址
+			continue
+		}
+		r.fromParent[key] = ln
+	}
+	return r.fromParent
+}
+
+// files 复制所有活跃监听器的文件描述符
+func (r *listenerRegistry) files() ([]listenerKey, []*os.File, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]listenerKey, 0, len(r.active))
+	files := make([]*os.File, 0, len(r.active))
+	for _, ln := range r.active {
+		filer, ok := ln.Listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+		// 父进程关闭 Unix 监听器时不删除套接字文件，新进程仍在使用它
+		if unix, ok := ln.Listener.(*net.UnixListener); ok {
+			unix.SetUnlinkOnClose(false)
+		}
+		f, err := filer.File()
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			return nil, nil, fmt.Errorf("%s: %w", ln.key, err)
+		}
+		keys = append(keys, ln.key)
+		files = append(files, f)
+	}
+	return keys, files, nil
+}
+
+// remove 监听器关闭后不再传给新进程
+func (r *listenerRegistry) remove(ln *trackedListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active = slices.DeleteFunc(r.active, func(l *trackedListener) bool { return l == ln })
+}
+
+// trackedListener 关闭时从注册表中移除的监听器
+type trackedListener struct {
+	net.Listener
+	key  listenerKey
+	once sync.Once
+}
+
+func (l *trackedListener) Close() error {
+	l.once.Do(func() { listeners.remove(l) })
+	return l.Listener.Close()
+}
+
+// listenerKey 以调用 Listen 时的参数 (而不是实际地址) 标识监听器，端口为 0 时新进程仍能匹配
+// Index 区分多个监听 ":0" 等相同参数的监听器，见 nextKey。
+type listenerKey struct {
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Index   int    `json:"index"`
+}
+
+func (k listenerKey) String() string {
+	return fmt.Sprintf("%s %s #%d", k.Network, k.Address, k.Index)
+}
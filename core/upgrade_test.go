@@ -0,0 +1,80 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app/core"
+)
+
+func TestUpgrade(t *testing.T) {
+	// 新进程：按调用顺序复用继承的监听器，就绪后每个监听器处理一个连接并退出
+	if os.Getenv("UPGRADE_TEST_CHILD") == "1" {
+		var wg sync.WaitGroup
+		for i := range 2 {
+			ln, err := core.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				os.Exit(1)
+			}
+			wg.Go(func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					os.Exit(1)
+				}
+				_, _ = fmt.Fprintf(conn, "new-%d", i)
+				conn.Close()
+			})
+		}
+		_ = core.UpgradeReady()
+		wg.Wait()
+		os.Exit(0)
+	}
+
+	// 以相同参数监听两次，新进程需要区分它们
+	var lns []net.Listener
+	for range 2 {
+		ln, err := core.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Listen failed: %v", err)
+		}
+		lns = append(lns, ln)
+	}
+
+	// 新进程只运行本测试
+	t.Setenv("UPGRADE_TEST_CHILD", "1")
+	args := os.Args
+	os.Args = []string{args[0], "-test.run=^TestUpgrade$"}
+	defer func() { os.Args = args }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pid, err := core.Upgrade(ctx)
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	if pid == os.Getpid() {
+		t.Fatalf("Expected a new process, got pid %d", pid)
+	}
+
+	// 旧进程关闭监听器后，新连接由新进程处理
+	for i, ln := range lns {
+		addr := ln.Addr().String()
+		ln.Close()
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			t.Fatalf("Expected listener %d to survive the upgrade: %v", i, err)
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		data, err := io.ReadAll(conn)
+		conn.Close()
+		if want := fmt.Sprintf("new-%d", i); err != nil || string(data) != want {
+			t.Errorf("Expected %q from new process, got %q (%v)", want, data, err)
+		}
+	}
+}
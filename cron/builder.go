@@ -78,6 +78,18 @@ func (b *Builder) AddJobWithDI(spec, name string, handler any) *Builder {
 	return b
 }
 
+// AddConfigJob 添加从配置读取调度表达式的任务
+// specKey 为配置项路径，例如 "jobs.cleanup.schedule"；重新加载配置后，
+// 表达式发生变化的任务会按新的表达式重新调度。handler 的写法与 AddJobWithDI 相同。
+func (b *Builder) AddConfigJob(specKey, name string, handler any) *Builder {
+	b.jobs = append(b.jobs, jobDefinition{
+		specKey: specKey,
+		name:    name,
+		handler: handler,
+	})
+	return b
+}
+
 // build 构建 CronService（内部使用）
 func (b *Builder) build(logger logging.Logger) (*service, error) {
 	// 创建 cronService
@@ -105,8 +117,7 @@ func (b *Builder) build(logger logging.Logger) (*service, error) {
 // 这里暂时保留以兼容 build 方法，但实际已在 service.go 中重新实现逻辑
 // 我们可以将逻辑移到 helper.go
 func wrapHandlerWithDI(container di.Container, logger logging.Logger, handler any) (func(), error) {
-	handlerValue := reflect.ValueOf(handler)
-	handlerType := handlerValue.Type()
+	handlerType := reflect.TypeOf(handler)
 
 	// 检查是否为函数
 	if handlerType.Kind() != reflect.Func {
@@ -115,40 +126,52 @@ func wrapHandlerWithDI(container di.Container, logger logging.Logger, handler an
 
 	// 返回包装函数
 	wrappedFunc := func() {
-		// 解析函数参数
-		numIn := handlerType.NumIn()
-		args := make([]reflect.Value, numIn)
-
-		for i := 0; i < numIn; i++ {
-			paramType := handlerType.In(i)
-
-			// 从容器获取实例
-			instance, err := container.Get(paramType)
-			if err != nil {
-				if logger != nil {
-					logger.Error(fmt.Sprintf("Failed to resolve parameter %d (%v) for cron job", i, paramType),
-						logging.Field{Key: "error", Value: err.Error()})
-				}
-				return
+		if err := invokeHandler(container, handler); err != nil {
+			if logger != nil {
+				logger.Error("Cron job failed", logging.Field{Key: "error", Value: err.Error()})
+			} else {
+				fmt.Println("Cron job failed:", err)
 			}
-
-			args[i] = reflect.ValueOf(instance)
 		}
+	}
 
-		// 调用处理函数
-		defer func() {
-			if r := recover(); r != nil {
-				if logger != nil {
-					logger.Error("Cron job panicked",
-						logging.Field{Key: "panic", Value: r})
-				} else {
-					fmt.Println("Cron job panicked:", r)
-				}
-			}
-		}()
+	return wrappedFunc, nil
+}
+
+// invokeHandler 从容器解析参数并调用任务函数
+// 任务函数最后一个返回值为 error 时返回该错误，panic 会被转换为错误。
+func invokeHandler(container di.Container, handler any) (err error) {
+	handlerValue := reflect.ValueOf(handler)
+	handlerType := handlerValue.Type()
 
-		handlerValue.Call(args)
+	// 解析函数参数
+	numIn := handlerType.NumIn()
+	args := make([]reflect.Value, numIn)
+
+	for i := 0; i < numIn; i++ {
+		paramType := handlerType.In(i)
+
+		// 从容器获取实例，参数对象 (di.In) 按字段解析
+		instance, err := di.ResolveParam(container, paramType)
+		if err != nil {
+			return fmt.Errorf("failed to resolve parameter %d (%v) for cron job: %w", i, paramType, err)
+		}
+
+		args[i] = instance
 	}
 
-	return wrappedFunc, nil
+	// 调用处理函数
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cron job panicked: %v", r)
+		}
+	}()
+
+	results := handlerValue.Call(args)
+	if n := len(results); n > 0 {
+		if last, ok := results[n-1].Interface().(error); ok {
+			return last
+		}
+	}
+	return nil
 }
@@ -0,0 +1,35 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gocrud/app/core"
+)
+
+// runCommand 创建 "cron run" 子命令，立即执行一次指定的任务
+// 任务在当前进程中同步执行，不会启动调度器。
+func runCommand(svc *service) core.Command {
+	return core.Command{
+		Name:  "cron run",
+		Usage: "<job>",
+		Short: "Run a cron job once and exit",
+		Run: func(ctx context.Context, inv *core.Invocation) error {
+			if len(inv.Args) != 1 {
+				return core.Usagef("cron run: expected exactly one job name, available jobs: %s", strings.Join(svc.jobNames(), ", "))
+			}
+
+			job, ok := svc.findJob(inv.Args[0])
+			if !ok {
+				return core.Usagef("cron run: unknown job %q, available jobs: %s", inv.Args[0], strings.Join(svc.jobNames(), ", "))
+			}
+
+			if err := invokeHandler(inv.Runtime.Container, job.handler); err != nil {
+				return fmt.Errorf("cron: job '%s' failed: %w", job.name, err)
+			}
+			fmt.Fprintf(inv.Stdout, "Cron job '%s' completed\n", job.name)
+			return nil
+		},
+	}
+}
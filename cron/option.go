@@ -3,7 +3,10 @@ package cron
 import (
 	"context"
 
+	"github.com/gocrud/app/config"
 	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/logging"
 )
 
 // BuilderOption 用于配置 Cron Builder
@@ -37,9 +40,17 @@ func AddJob(spec, name string, handler any) BuilderOption {
 	}
 }
 
+// AddConfigJob 添加从配置读取调度表达式的任务，配置重新加载后自动重新调度
+func AddConfigJob(specKey, name string, handler any) BuilderOption {
+	return func(b *Builder) {
+		b.AddConfigJob(specKey, name, handler)
+	}
+}
+
 // New 启用 Cron 能力
+// 依赖 logging 模块，任务日志使用 "cron" 分类输出。
 func New(opts ...BuilderOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("cron", func(rt *core.Runtime) error {
 		builder := NewBuilder()
 		for _, opt := range opts {
 			opt(builder)
@@ -56,36 +67,61 @@ func New(opts ...BuilderOption) core.Option {
 			return err
 		}
 
+		// 调度表达式在启动及重新加载时从配置读取
+		svc.lookupSpec = func(key string) string {
+			if cfg := config.From(rt); cfg != nil {
+				return cfg.Get(key)
+			}
+			return ""
+		}
+
+		rt.AddDiagnostics("cron", func() any { return svc.entries() })
+
 		// 注册为 Host Service (后台运行)
 		// 使用 Runtime 的 Lifecycle
-		rt.Lifecycle.OnStart(func(ctx context.Context) error {
-			// 注入 DI 容器和 Logger 到 svc
-			// 我们的 builder.build() 可能返回了一个未完全初始化的 svc
-			// 这里需要进行一些 hack 或者重构 build 逻辑
-			// 更好的方式是：builder 只是收集 Job 定义，真正的构建发生在 OnStart
-			
-			// 重新设计 build: 
-			// cronSvc 依赖 logger 和 container (用于 DI Job)
-			// 我们在 OnStart 时构建它
-			
-			// 由于 builder.build 在原设计中返回 HostedService，我们先重构 builder
-			
-			// 临时方案：builder.build 不真正创建 cron 实例，而是返回一个 Config 对象？
-			// 或者让 builder 保持配置，Start 时再初始化
-			
-			// 让 svc 初始化
-			svc.Inject(rt.Container, nil) // 需要给 svc 加一个注入方法
-			
-			return svc.Start(ctx)
-		})
-
-		rt.Lifecycle.OnStop(func(ctx context.Context) error {
-			return svc.Stop(ctx)
+		rt.Lifecycle.Append(core.Hook{
+			Name:  "cron",
+			Stage: core.StageHosts,
+			OnStart: func(ctx context.Context) error {
+				// 注入 DI 容器和 Logger 到 svc
+				// 我们的 builder.build() 可能返回了一个未完全初始化的 svc
+				// 这里需要进行一些 hack 或者重构 build 逻辑
+				// 更好的方式是：builder 只是收集 Job 定义，真正的构建发生在 OnStart
+				
+				// 重新设计 build: 
+				// cronSvc 依赖 logger 和 container (用于 DI Job)
+				// 我们在 OnStart 时构建它
+				
+				// 由于 builder.build 在原设计中返回 HostedService，我们先重构 builder
+				
+				// 临时方案：builder.build 不真正创建 cron 实例，而是返回一个 Config 对象？
+				// 或者让 builder 保持配置，Start 时再初始化
+				
+				// 让 svc 初始化
+				logger, err := di.Get[logging.Logger](rt.Container)
+				if err != nil {
+					return err
+				}
+				svc.Inject(rt.Container, logger.WithCategory("cron"))
+				
+				return svc.Start(ctx)
+			},
+			OnStop: func(ctx context.Context) error {
+				return svc.Stop(ctx)
+			},
+			OnReload: func(ctx context.Context) error {
+				return svc.reload()
+			},
 		})
 		
 		// 注册为特性
 		rt.Features.Set(svc)
 
+		// 注册 "cron run" 子命令
+		if err := core.CommandsFrom(rt).Add(runCommand(svc)); err != nil {
+			return err
+		}
+
 		return nil
-	}
+	}, "logging"))
 }
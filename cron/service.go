@@ -2,8 +2,12 @@ package cron
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gocrud/app/di"
 	"github.com/gocrud/app/logging"
@@ -13,6 +17,7 @@ import (
 // jobDefinition 任务定义
 type jobDefinition struct {
 	spec    string
+	specKey string // 调度表达式所在的配置项，非空时忽略 spec
 	name    string
 	handler any
 }
@@ -24,8 +29,12 @@ type service struct {
 	logger    logging.Logger
 	mu        sync.RWMutex
 	jobs      map[string]cron.EntryID // 任务名称到任务ID的映射
-	jobDefs   []jobDefinition         // 暂存任务定义
+	jobDefs   []jobDefinition         // 任务定义
+	specs     map[string]string       // 任务名称到当前调度表达式的映射
+	funcs     map[string]func()       // 任务名称到已包装处理函数的映射
 	container di.Container            // 依赖注入容器
+	// lookupSpec 读取配置项中的调度表达式
+	lookupSpec func(key string) string
 }
 
 // options Cron 服务配置选项
@@ -53,37 +62,44 @@ func newService(logger logging.Logger, opts ...func(*options)) *service {
 		o(opt)
 	}
 
+	s := &service{
+		logger: opt.Logger,
+		jobs:   make(map[string]cron.EntryID),
+		specs:  make(map[string]string),
+		funcs:  make(map[string]func()),
+	}
+
 	// 配置 cron 选项
+	// cron 库的日志适配器引用 service 当前的 logger，以便 Inject 替换后生效
 	cronOpts := []cron.Option{}
 
 	// 只在启用时添加 cron 库的日志记录器
 	if opt.EnableCronLogger {
-		cronOpts = append(cronOpts, cron.WithLogger(newCronLogger(opt.Logger)))
+		cronOpts = append(cronOpts, cron.WithLogger(newCronLogger(s)))
 	}
 
 	cronOpts = append(cronOpts, cron.WithChain(
-		cron.Recover(newCronLogger(opt.Logger)),
+		cron.Recover(newCronLogger(s)),
 	))
 
 	if opt.EnableSeconds {
 		cronOpts = append(cronOpts, cron.WithSeconds())
 	}
 
-	return &service{
-		cron:   cron.New(cronOpts...),
-		logger: opt.Logger,
-		jobs:   make(map[string]cron.EntryID),
-	}
+	s.cron = cron.New(cronOpts...)
+	return s
 }
 
 // addJob 添加定时任务
 // spec: cron 表达式，如 "0 */5 * * * *" (每5分钟) 或 "0 0 2 * * *" (每天凌晨2点)
 // name: 任务名称（用于管理和日志）
 // job: 任务函数
+// 同名任务已存在时，新的调度添加成功后才会移除旧的调度。
 func (s *service) addJob(spec, name string, job func()) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	previous, exists := s.jobs[name]
 	entryID, err := s.cron.AddFunc(spec, func() {
 		s.logger.Info(fmt.Sprintf("Cron job '%s' started", name))
 		defer s.logger.Info(fmt.Sprintf("Cron job '%s' completed", name))
@@ -94,7 +110,12 @@ func (s *service) addJob(spec, name string, job func()) error {
 		return fmt.Errorf("failed to add cron job '%s': %w", name, err)
 	}
 
+	if exists {
+		s.cron.Remove(previous)
+	}
 	s.jobs[name] = entryID
+	s.specs[name] = spec
+	s.funcs[name] = job
 	s.logger.Info(fmt.Sprintf("Cron job '%s' registered with spec '%s'", name, spec))
 	return nil
 }
@@ -107,10 +128,61 @@ func (s *service) removeJob(name string) {
 	if entryID, exists := s.jobs[name]; exists {
 		s.cron.Remove(entryID)
 		delete(s.jobs, name)
+		delete(s.specs, name)
+		delete(s.funcs, name)
 		s.logger.Info(fmt.Sprintf("Cron job '%s' removed", name))
 	}
 }
 
+// findJob 按名称查找任务定义
+func (s *service) findJob(name string) (jobDefinition, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, job := range s.jobDefs {
+		if job.name == name {
+			return job, true
+		}
+	}
+	return jobDefinition{}, false
+}
+
+// jobNames 返回所有任务名称
+func (s *service) jobNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.jobDefs))
+	for _, job := range s.jobDefs {
+		names = append(names, job.name)
+	}
+	return names
+}
+
+// entryInfo 已调度任务的诊断信息
+type entryInfo struct {
+	Name string    `json:"name"`
+	Spec string    `json:"spec"`
+	Next time.Time `json:"next"`
+	Prev time.Time `json:"prev"`
+}
+
+// entries 按名称返回已调度的任务及其上次与下次执行时间，调度器启动前时间为零值
+func (s *service) entries() []entryInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]entryInfo, 0, len(s.jobs))
+	for name, id := range s.jobs {
+		entry := s.cron.Entry(id)
+		infos = append(infos, entryInfo{Name: name, Spec: s.specs[name], Next: entry.Next, Prev: entry.Prev})
+	}
+	slices.SortFunc(infos, func(a, b entryInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return infos
+}
+
 // Inject 注入依赖
 func (s *service) Inject(container di.Container, logger logging.Logger) {
 	s.container = container
@@ -152,18 +224,65 @@ func (s *service) Start(ctx context.Context) error {
 			handlerFunc = wrapped
 		}
 
-		if err := s.addJob(job.spec, job.name, handlerFunc); err != nil {
+		spec, err := s.jobSpec(job)
+		if err != nil {
+			return err
+		}
+		if err := s.addJob(spec, job.name, handlerFunc); err != nil {
 			return err
 		}
 	}
 
-	// 清空定义以释放内存
-	s.jobDefs = nil
-
 	s.cron.Start()
 	return nil
 }
 
+// jobSpec 返回任务的调度表达式，从配置读取的任务使用配置项的当前值
+func (s *service) jobSpec(job jobDefinition) (string, error) {
+	if job.specKey == "" {
+		return job.spec, nil
+	}
+	spec := ""
+	if s.lookupSpec != nil {
+		spec = s.lookupSpec(job.specKey)
+	}
+	if spec == "" {
+		return "", fmt.Errorf("cron: job '%s' has no schedule, config key '%s' is empty", job.name, job.specKey)
+	}
+	return spec, nil
+}
+
+// reload 重新读取从配置获取调度表达式的任务，并重新调度发生变化的任务
+// 新的表达式无效时保留原有调度，并返回错误。
+func (s *service) reload() error {
+	var errs []error
+	for _, job := range s.jobDefs {
+		if job.specKey == "" {
+			continue
+		}
+
+		s.mu.RLock()
+		current, fn := s.specs[job.name], s.funcs[job.name]
+		s.mu.RUnlock()
+		if fn == nil {
+			continue
+		}
+
+		spec, err := s.jobSpec(job)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w, keeping schedule '%s'", err, current))
+			continue
+		}
+		if spec == current {
+			continue
+		}
+		if err := s.addJob(spec, job.name, fn); err != nil {
+			errs = append(errs, fmt.Errorf("%w, keeping schedule '%s'", err, current))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Stop 实现 HostedService.Stop
 func (s *service) Stop(ctx context.Context) error {
 	if s.logger != nil {
@@ -185,21 +304,28 @@ func (s *service) Stop(ctx context.Context) error {
 
 // cronLogger 适配器：将框架日志接口适配到 cron 的日志接口
 type cronLogger struct {
-	logger logging.Logger
+	svc *service
 }
 
-func newCronLogger(logger logging.Logger) cron.Logger {
-	return &cronLogger{logger: logger}
+func newCronLogger(svc *service) cron.Logger {
+	return &cronLogger{svc: svc}
 }
 
 func (l *cronLogger) Info(msg string, keysAndValues ...interface{}) {
-	l.logger.Info(msg, convertToFields(keysAndValues)...)
+	if l.svc.logger == nil {
+		return
+	}
+	l.svc.logger.Info(msg, convertToFields(keysAndValues)...)
 }
 
 func (l *cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
 	fields := convertToFields(keysAndValues)
 	fields = append(fields, logging.Field{Key: "error", Value: err.Error()})
-	l.logger.Error(msg, fields...)
+	if l.svc.logger == nil {
+		fmt.Printf("%s: %v\n", msg, err)
+		return
+	}
+	l.svc.logger.Error(msg, fields...)
 }
 
 func convertToFields(keysAndValues []interface{}) []logging.Field {
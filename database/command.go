@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gocrud/app/core"
+)
+
+// migrateCommand 创建 "migrate up" 子命令，对配置了 AutoMigrate 的数据库执行迁移
+func migrateCommand(factory *DatabaseFactory) core.Command {
+	var name string
+	return core.Command{
+		Name:  "migrate up",
+		Short: "Apply AutoMigrate models to the configured databases",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&name, "db", "", "only migrate the named database")
+		},
+		Run: func(ctx context.Context, inv *core.Invocation) error {
+			if err := factory.Migrate(name); err != nil {
+				return fmt.Errorf("database: %w", err)
+			}
+			fmt.Fprintln(inv.Stdout, "Migrations applied")
+			return nil
+		},
+	}
+}
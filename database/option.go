@@ -6,6 +6,7 @@ import (
 
 	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
 	"gorm.io/gorm"
 )
 
@@ -30,7 +31,7 @@ func WithDatabase(name string, dialector gorm.Dialector, opts ...func(*DatabaseO
 
 // New 启用数据库能力
 func New(opts ...BuilderOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("database", func(rt *core.Runtime) error {
 		builder := NewBuilder()
 		for _, opt := range opts {
 			opt(builder)
@@ -53,6 +54,7 @@ func New(opts ...BuilderOption) core.Option {
 		}
 
 		// 4. 注册各个数据库实例到 DI
+		registry := health.RegistryFrom(rt)
 		var defaultRegErr error
 		factory.Each(func(name string, db *gorm.DB) {
 			// 注册命名实例
@@ -68,19 +70,37 @@ func New(opts ...BuilderOption) core.Option {
 					defaultRegErr = err
 				}
 			}
+
+			// 注册健康检查
+			if err := registry.RegisterFunc("database:"+name, func(ctx context.Context) error {
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			}); err != nil {
+				defaultRegErr = err
+			}
 		})
 
 		if defaultRegErr != nil {
 			return fmt.Errorf("database: failed to register instance: %w", defaultRegErr)
 		}
 
-		// 5. 注册清理钩子
+		rt.AddDiagnostics("database", func() any { return factory.Stats() })
+
+		// 5. 注册 "migrate up" 子命令
+		if err := core.CommandsFrom(rt).Add(migrateCommand(factory)); err != nil {
+			return err
+		}
+
+		// 6. 注册清理钩子
 		rt.Lifecycle.OnStop(func(ctx context.Context) error {
 			// 这里简单打印日志，实际日志应该从容器获取 Logger
 			fmt.Println("Closing database connections")
 			return factory.Close()
-		})
+		}, core.WithHookName("database"), core.InStage(core.StageInfrastructure))
 
 		return nil
-	}
+	}))
 }
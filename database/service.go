@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"sync"
 	"time"
@@ -45,14 +46,16 @@ func (o *DatabaseOptions) Validate() error {
 
 // DatabaseFactory 数据库客户端工厂
 type DatabaseFactory struct {
-	dbs map[string]*gorm.DB
-	mu  sync.RWMutex
+	dbs    map[string]*gorm.DB
+	models map[string][]any // 每个数据库需要自动迁移的模型
+	mu     sync.RWMutex
 }
 
 // NewDatabaseFactory 创建数据库工厂
 func NewDatabaseFactory() *DatabaseFactory {
 	return &DatabaseFactory{
-		dbs: make(map[string]*gorm.DB),
+		dbs:    make(map[string]*gorm.DB),
+		models: make(map[string][]any),
 	}
 }
 
@@ -89,6 +92,31 @@ func (f *DatabaseFactory) Register(opts DatabaseOptions) error {
 	}
 
 	f.dbs[opts.Name] = db
+	f.models[opts.Name] = opts.AutoMigrate
+	return nil
+}
+
+// Migrate 对指定数据库执行自动迁移，name 为空时迁移所有数据库
+func (f *DatabaseFactory) Migrate(name string) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if name != "" {
+		if _, exists := f.dbs[name]; !exists {
+			return fmt.Errorf("database '%s' not registered", name)
+		}
+	}
+
+	for dbName, db := range f.dbs {
+		if name != "" && dbName != name {
+			continue
+		}
+		if models := f.models[dbName]; len(models) > 0 {
+			if err := db.AutoMigrate(models...); err != nil {
+				return fmt.Errorf("auto migrate failed for '%s': %w", dbName, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -102,6 +130,20 @@ func (f *DatabaseFactory) Each(fn func(name string, db *gorm.DB)) {
 	}
 }
 
+// Stats 返回每个数据库实例的连接池状态
+func (f *DatabaseFactory) Stats() map[string]sql.DBStats {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	stats := make(map[string]sql.DBStats, len(f.dbs))
+	for name, db := range f.dbs {
+		if sqlDB, err := db.DB(); err == nil {
+			stats[name] = sqlDB.Stats()
+		}
+	}
+	return stats
+}
+
 // Close 关闭所有数据库连接
 func (f *DatabaseFactory) Close() error {
 	f.mu.Lock()
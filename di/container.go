@@ -1,10 +1,13 @@
 package di
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Container 是依赖注入容器的接口。
@@ -24,27 +27,76 @@ type Container interface {
 	// CreateScope 为作用域实例创建一个新作用域。
 	CreateScope() Scope
 
+	// Tagged 按注册顺序返回带有指定标签的服务。
+	Tagged(tag string) []ServiceKey
+
+	// GetGroup 按注册顺序解析组内的所有服务，返回元素类型为 typ 的切片。
+	GetGroup(typ reflect.Type, group string) (any, error)
+
+	// Services 按注册顺序返回所有服务的描述（用于诊断）。
+	Services() []ServiceInfo
+
+	// Close 按创建的相反顺序释放容器创建的单例（Disposable 或 io.Closer），
+	// 返回所有释放错误的组合。关闭后不应再使用容器。
+	// 从根容器直接创建的瞬态实例不会被记录，由调用方负责释放。
+	Close(ctx context.Context) error
+
 	// serviceCount 返回注册服务的总数（用于数组大小调整）。
 	serviceCount() int
+
+	// addDecorator 为服务注册装饰器（见 Decorate）。
+	addDecorator(key ServiceKey, fn any) error
 }
 
 // container 是具体的实现。
 type container struct {
 	mu              sync.RWMutex
 	definitions     map[ServiceKey]*ServiceDefinition
+	keys            []ServiceKey            // 按注册顺序排列的服务键
+	groups          map[string][]ServiceKey // 组名到按注册顺序排列的成员
+	decorators      map[ServiceKey][]*decorator
 	built           atomic.Bool
 	serviceCountVal int
 
 	// resolver 处理实例的创建
 	resolver *resolver
+
+	// disposer 记录需要在 Close 时释放的实例
+	disposer disposer
+
+	// buildObserver 在 Build 期间每个单例构建完成后调用
+	buildObserver func(BuildEvent)
+}
+
+// ContainerOption 配置容器。
+type ContainerOption func(*container)
+
+// BuildEvent 描述 Build 期间一个单例的构建。
+type BuildEvent struct {
+	Key      ServiceKey
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// WithBuildObserver 在 Build 急切初始化每个单例后调用 fn（用于启动耗时分析）。
+// 以 WithValue 注册的现成实例不需要构建，不会触发回调。
+func WithBuildObserver(fn func(BuildEvent)) ContainerOption {
+	return func(c *container) {
+		c.buildObserver = fn
+	}
 }
 
 // NewContainer 创建一个新的空容器。
-func NewContainer() Container {
-	return &container{
+func NewContainer(opts ...ContainerOption) Container {
+	c := &container{
 		definitions: make(map[ServiceKey]*ServiceDefinition),
 		resolver:    newResolver(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Add 向容器添加服务定义。
@@ -56,6 +108,11 @@ func (c *container) Add(def *ServiceDefinition) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// 未命名的组成员使用 "组名[序号]" 作为名称，避免同类型成员之间以及与普通注册冲突
+	if def.Group != "" && def.Name == "" {
+		def.Name = fmt.Sprintf("%s[%d]", def.Group, len(c.groups[def.Group]))
+	}
+
 	key := ServiceKey{Type: def.Type, Name: def.Name}
 
 	if _, exists := c.definitions[key]; exists {
@@ -66,6 +123,28 @@ func (c *container) Add(def *ServiceDefinition) error {
 	}
 
 	c.definitions[key] = def
+	c.keys = append(c.keys, key)
+	if def.Group != "" {
+		if c.groups == nil {
+			c.groups = make(map[string][]ServiceKey)
+		}
+		c.groups[def.Group] = append(c.groups[def.Group], key)
+	}
+	return nil
+}
+
+// addDecorator 为服务注册装饰器，装饰器在 Build 时关联到服务定义。
+func (c *container) addDecorator(key ServiceKey, fn any) error {
+	if c.built.Load() {
+		return fmt.Errorf("di: build 后无法注册装饰器")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.decorators == nil {
+		c.decorators = make(map[ServiceKey][]*decorator)
+	}
+	c.decorators[key] = append(c.decorators[key], &decorator{fn: fn})
 	return nil
 }
 
@@ -82,7 +161,17 @@ func (c *container) Build() error {
 		return nil
 	}
 
-	// 0. 为定义分配 ID
+	// 0. 关联装饰器
+	for key, decorators := range c.decorators {
+		def, ok := c.definitions[key]
+		if !ok {
+			c.mu.Unlock()
+			return fmt.Errorf("di: 装饰的服务 %v 未注册", key)
+		}
+		def.decorators = decorators
+	}
+
+	// 为定义分配 ID
 	c.serviceCountVal = 0
 	// 为了确保确定性顺序（虽然 map 迭代是随机的），
 	// 只要 ID 唯一且在构建后一致，分配顺序并不重要。
@@ -109,7 +198,12 @@ func (c *container) Build() error {
 	for _, key := range order {
 		def := c.definitions[key]
 		if def.Scope == ScopeSingleton {
-			if _, err := c.GetNamed(key.Type, key.Name); err != nil {
+			start := time.Now()
+			_, err := c.GetNamed(key.Type, key.Name)
+			if c.buildObserver != nil && !def.IsValue {
+				c.buildObserver(BuildEvent{Key: key, Start: start, Duration: time.Since(start), Err: err})
+			}
+			if err != nil {
 				return fmt.Errorf("di: 构建单例 %v (name=%s) 失败: %w", key.Type, key.Name, err)
 			}
 		}
@@ -146,11 +240,16 @@ func (c *container) GetNamed(typ reflect.Type, name string) (any, error) {
 	if def.Scope == ScopeSingleton {
 		def.singletonOnce.Do(func() {
 			def.singletonInst, def.singletonErr = c.resolver.createInstance(c, def)
+			if def.singletonErr == nil && !def.IsValue {
+				c.disposer.track(def.singletonInst)
+			}
+			def.singletonDone.Store(true)
 		})
 		return def.singletonInst, def.singletonErr
 	}
 
 	if def.Scope == ScopeTransient {
+		// 从根容器创建的瞬态实例由调用方负责释放，记录它们会让容器持有引用直到关闭
 		return c.resolver.createInstance(c, def)
 	}
 
@@ -166,6 +265,53 @@ func (c *container) CreateScope() Scope {
 	return newScope(c)
 }
 
+// Close 按创建的相反顺序释放容器创建的实例。
+func (c *container) Close(ctx context.Context) error {
+	return c.disposer.dispose(ctx)
+}
+
+// Tagged 按注册顺序返回带有指定标签的服务。
+func (c *container) Tagged(tag string) []ServiceKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []ServiceKey
+	for _, key := range c.keys {
+		if slices.Contains(c.definitions[key].Tags, tag) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// GetGroup 按注册顺序解析组内的所有服务。
+func (c *container) GetGroup(typ reflect.Type, group string) (any, error) {
+	if !c.built.Load() {
+		return nil, fmt.Errorf("di: 容器未构建")
+	}
+	return resolveGroup(c, c.groupMembers(group), typ, group)
+}
+
+// groupMembers 返回组成员的服务键，构建后组成员不再变化
+func (c *container) groupMembers(group string) []ServiceKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.groups[group]
+}
+
+// Services 按注册顺序返回所有服务的描述。
+// 依赖关系在 Build 时分析，构建前返回的描述不包含依赖。
+func (c *container) Services() []ServiceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	infos := make([]ServiceInfo, 0, len(c.keys))
+	for _, key := range c.keys {
+		infos = append(infos, describe(key, c.definitions[key]))
+	}
+	return infos
+}
+
 func (c *container) serviceCount() int {
 	return c.serviceCountVal
 }
@@ -0,0 +1,85 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decorator 已注册的装饰器
+type decorator struct {
+	fn     any
+	schema *InjectionSchema // 在 Build 时分析，Args[0] 为被装饰的实例
+}
+
+// Decorate 为类型为 T 的服务（名称为空）添加装饰器，decorator 的形式为 func(inner T, deps...) T 或 func(inner T, deps...) (T, error)。
+// 其余参数与构造函数一样从容器注入（支持 di.In 参数对象）。
+//
+//	di.Decorate(rt.Container, func(inner UserRepository, cache *redis.Client) UserRepository {
+//	    return &cachedUserRepository{inner: inner, cache: cache}
+//	})
+//
+// 装饰器在服务解析时按注册顺序依次执行，并遵循被装饰服务的作用域：单例只装饰一次，
+// 作用域服务在每个作用域中装饰一次，瞬态服务每次解析都会装饰。
+// 以 WithValue 注册的实例同样可以被装饰。装饰器必须在 Build 之前注册，注册时服务可以尚未注册。
+// 值组的成员以内部生成的名称注册，无法被单独装饰。
+// 容器只释放最终（最外层）的实例，包装类型需要自行转发 Close 以释放内部实例；
+// 以 WithValue 注册的实例装饰后仍由注册方负责释放。
+func Decorate(c Container, decorator any) error {
+	return DecorateNamed(c, "", decorator)
+}
+
+// DecorateNamed 为指定名称的服务添加装饰器，用法同 Decorate。
+func DecorateNamed(c Container, name string, decorator any) error {
+	typ, err := decoratedType(decorator)
+	if err != nil {
+		return err
+	}
+	return c.addDecorator(ServiceKey{Type: typ, Name: name}, decorator)
+}
+
+// decoratedType 校验装饰器的签名并返回被装饰的类型
+func decoratedType(fn any) (reflect.Type, error) {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("di: 装饰器必须是函数，得到 %v", fnType)
+	}
+	if fnType.NumIn() == 0 || fnType.NumOut() == 0 || fnType.NumOut() > 2 {
+		return nil, fmt.Errorf("di: 装饰器 %v 的形式必须为 func(T, ...) T 或 func(T, ...) (T, error)", fnType)
+	}
+
+	typ := fnType.In(0)
+	if fnType.Out(0) != typ {
+		return nil, fmt.Errorf("di: 装饰器 %v 的返回值类型必须与第一个参数相同", fnType)
+	}
+	if fnType.NumOut() == 2 && fnType.Out(1) != reflect.TypeFor[error]() {
+		return nil, fmt.Errorf("di: 装饰器 %v 的第二个返回值必须是 error", fnType)
+	}
+	if IsIn(typ) {
+		return nil, fmt.Errorf("di: 装饰器 %v 的第一个参数不能是参数对象", fnType)
+	}
+	return typ, nil
+}
+
+// decorate 按注册顺序对实例应用服务的装饰器
+func (r *resolver) decorate(c Container, def *ServiceDefinition, instance any) (any, error) {
+	for _, d := range def.decorators {
+		args := make([]reflect.Value, len(d.schema.Args))
+		args[0] = reflect.New(def.Type).Elem()
+		if instance != nil {
+			args[0].Set(reflect.ValueOf(instance))
+		}
+		for i := 1; i < len(args); i++ {
+			arg, err := r.resolveArg(c, d.schema, i)
+			if err != nil {
+				return nil, fmt.Errorf("装饰器 %T: %w", d.fn, err)
+			}
+			args[i] = arg
+		}
+
+		var err error
+		if instance, err = callFunction(reflect.ValueOf(d.fn), args); err != nil {
+			return nil, fmt.Errorf("装饰器 %T: %w", d.fn, err)
+		}
+	}
+	return instance, nil
+}
@@ -1,8 +1,10 @@
 package di
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 )
 
 // ScopeType 定义了服务的生命周期。
@@ -17,12 +19,34 @@ const (
 	ScopeScoped
 )
 
+// String 返回作用域名称。
+func (s ScopeType) String() string {
+	switch s {
+	case ScopeSingleton:
+		return "singleton"
+	case ScopeTransient:
+		return "transient"
+	case ScopeScoped:
+		return "scoped"
+	default:
+		return fmt.Sprintf("scope(%d)", int(s))
+	}
+}
+
 // ServiceKey 是服务映射的唯一键。
 type ServiceKey struct {
 	Type reflect.Type
 	Name string
 }
 
+// String 返回服务键的可读形式，例如 "*gorm.DB (name=reporting)"。
+func (k ServiceKey) String() string {
+	if k.Name == "" {
+		return k.Type.String()
+	}
+	return fmt.Sprintf("%v (name=%s)", k.Type, k.Name)
+}
+
 // FieldInjection 包含需要注入的结构体字段的元数据。
 type FieldInjection struct {
 	Index       int
@@ -30,12 +54,15 @@ type FieldInjection struct {
 	Type        reflect.Type
 	Optional    bool
 	ServiceName string // 注入的服务名称
+	Group       string // 非空时注入组内所有服务组成的切片，Type 为切片类型
 }
 
 // InjectionSchema 包含预计算的注入元数据。
 type InjectionSchema struct {
 	Fields []FieldInjection // 用于结构体注入
 	Args   []reflect.Type   // 用于函数/工厂注入
+	// ArgFields 与 Args 一一对应，参数对象 (di.In) 为需要注入的字段，普通参数为 nil
+	ArgFields [][]FieldInjection
 }
 
 // ServiceDefinition 包含注册服务的元数据。
@@ -48,12 +75,86 @@ type ServiceDefinition struct {
 	Impl         any          // 工厂函数或结构体指针
 	IsFactory    bool
 	IsValue      bool
-	InjectFields bool // 是否对 IsValue 的实例执行字段注入
+	InjectFields bool     // 是否对 IsValue 的实例执行字段注入
+	Tags         []string // 服务标签，可通过 Container.Tagged 按标签查找
+	Group        string   // 服务所在的组，可通过 Container.GetGroup 解析
 
 	Schema *InjectionSchema // 预计算的依赖图
 
+	// 结果对象 (di.Out) 的字段服务：从 source 解析结果对象后取出第 sourceField 个字段
+	source      *ServiceDefinition
+	sourceField int
+
+	// decorators 按注册顺序排列的装饰器，在 Build 时从容器关联
+	decorators []*decorator
+
 	// 用于单例作用域
 	singletonInst any
 	singletonErr  error
 	singletonOnce sync.Once
+	singletonDone atomic.Bool // 单例是否已创建（包括创建失败）
+}
+
+// Dependency 描述服务的一个依赖。
+type Dependency struct {
+	Key      ServiceKey
+	Optional bool
+}
+
+// ServiceInfo 描述已注册的服务，用于诊断与依赖图输出。
+type ServiceInfo struct {
+	Key          ServiceKey
+	Scope        ScopeType
+	Tags         []string
+	Group        string
+	Dependencies []Dependency
+	// Instantiated 单例是否已经创建，直接注册的实例总是为 true；作用域与瞬态服务总是为 false
+	Instantiated bool
+}
+
+// describe 根据服务定义生成描述
+func describe(key ServiceKey, def *ServiceDefinition) ServiceInfo {
+	info := ServiceInfo{Key: key, Scope: def.Scope, Tags: def.Tags, Group: def.Group}
+	info.Instantiated = def.Scope == ScopeSingleton && (def.IsValue || def.singletonDone.Load())
+	if def.source != nil {
+		info.Dependencies = []Dependency{{Key: ServiceKey{Type: def.source.Type, Name: def.source.Name}}}
+		return info
+	}
+	if def.Schema != nil {
+		info.Dependencies = append(info.Dependencies, def.Schema.dependencies(0)...)
+	}
+	// 装饰器的第一个参数是被装饰的实例本身
+	for _, d := range def.decorators {
+		if d.schema != nil {
+			info.Dependencies = append(info.Dependencies, d.schema.dependencies(1)...)
+		}
+	}
+	return info
+}
+
+// dependencies 返回 schema 中从第 from 个参数开始的参数与所有字段对应的依赖
+func (s *InjectionSchema) dependencies(from int) []Dependency {
+	var deps []Dependency
+	for i := from; i < len(s.Args); i++ {
+		if i >= len(s.ArgFields) || s.ArgFields[i] == nil {
+			deps = append(deps, Dependency{Key: ServiceKey{Type: s.Args[i]}})
+			continue
+		}
+		for _, field := range s.ArgFields[i] {
+			deps = append(deps, field.dependency())
+		}
+	}
+	for _, field := range s.Fields {
+		deps = append(deps, field.dependency())
+	}
+	return deps
+}
+
+// dependency 返回字段对应的依赖，组依赖以 "group:组名" 作为名称
+func (f FieldInjection) dependency() Dependency {
+	name := f.ServiceName
+	if f.Group != "" {
+		name = "group:" + f.Group
+	}
+	return Dependency{Key: ServiceKey{Type: f.Type, Name: name}, Optional: f.Optional}
 }
@@ -10,6 +10,7 @@ import (
 //
 // Supported targets:
 // 1. func(...) (Service, error?) -> Registered as Factory. ServiceType is the first return value.
+//   - If Service is a struct embedding di.Out, each of its fields is registered as a service as well.
 // 2. *Struct                      -> Registered as Value (Singleton). ServiceType is *Struct.
 //   - If struct has fields with `di` tag, field injection is enabled.
 //
@@ -83,6 +84,11 @@ func Provide(c Container, target any, opts ...Option) (reflect.Type, error) {
 		opt(def)
 	}
 
+	// Result objects register one service per field, sharing the constructor call
+	if def.IsFactory && IsOut(serviceType) {
+		return serviceType, provideOut(c, def)
+	}
+
 	// Register
 	if err := c.Add(def); err != nil {
 		return nil, err
@@ -147,16 +153,15 @@ func Invoke(c Container, fn any) error {
 	}
 	fnType := fnVal.Type()
 
-	// Prepare arguments
+	// Prepare arguments (parameter objects embedding di.In are resolved field by field)
 	args := make([]reflect.Value, fnType.NumIn())
 	for i := 0; i < fnType.NumIn(); i++ {
 		argType := fnType.In(i)
-		// Currently Invoke only supports type-based injection
-		val, err := c.Get(argType)
+		val, err := ResolveParam(c, argType)
 		if err != nil {
 			return fmt.Errorf("di: failed to resolve argument %d (%v): %w", i, argType, err)
 		}
-		args[i] = reflect.ValueOf(val)
+		args[i] = val
 	}
 
 	// Call function
@@ -1,7 +1,10 @@
 package di_test
 
 import (
+	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/gocrud/app/di"
@@ -268,3 +271,196 @@ func TestProvideDuplicates(t *testing.T) {
 		t.Error("Expected error when registering duplicate service, got nil")
 	}
 }
+
+// closeRecorder 记录释放顺序，name 为 "broken" 时释放失败
+type closeRecorder struct {
+	name   string
+	closed *[]string
+}
+
+func (r *closeRecorder) Close() error {
+	*r.closed = append(*r.closed, r.name)
+	if r.name == "broken" {
+		return errors.New("close failed")
+	}
+	return nil
+}
+
+type disposeRecorder struct {
+	closeRecorder
+}
+
+func (r *disposeRecorder) Dispose(ctx context.Context) error {
+	*r.closed = append(*r.closed, "dispose:"+r.name)
+	return nil
+}
+
+type DisposeDep struct{ *closeRecorder }
+
+type DisposeOwner struct{ *closeRecorder }
+
+type DisposeScoped struct{ *closeRecorder }
+
+type DisposeAlias interface{ Close() error }
+
+func TestDisposal(t *testing.T) {
+	var closed []string
+	c := di.NewContainer()
+	di.Provide(c, func() *DisposeDep { return &DisposeDep{&closeRecorder{"dep", &closed}} })
+	di.Provide(c, func(*DisposeDep) *DisposeOwner { return &DisposeOwner{&closeRecorder{"broken", &closed}} })
+	di.Provide(c, func(d *DisposeDep) DisposeAlias { return d })
+	di.Provide(c, func() *disposeRecorder { return &disposeRecorder{closeRecorder{"both", &closed}} }, di.WithTransient())
+	di.Provide(c, func(*disposeRecorder) *DisposeScoped {
+		return &DisposeScoped{&closeRecorder{"scoped", &closed}}
+	}, di.WithScoped())
+	di.ProvideService[*closeRecorder](c, di.WithValue(&closeRecorder{"value", &closed}))
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	scope := c.CreateScope()
+	if _, err := di.Get[*DisposeScoped](scope); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, err := di.Get[DisposeAlias](scope); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := scope.Dispose(context.Background()); err != nil {
+		t.Fatalf("Dispose failed: %v", err)
+	}
+	if got := strings.Join(closed, ","); got != "scoped,dispose:both" {
+		t.Errorf("Expected scope to dispose its instances in reverse order, got %s", got)
+	}
+	if _, err := di.Get[*DisposeScoped](scope); !errors.Is(err, di.ErrScopeDisposed) {
+		t.Errorf("Expected ErrScopeDisposed after Dispose, got %v", err)
+	}
+
+	// 从根容器创建的瞬态实例由调用方释放
+	if _, err := di.Get[*disposeRecorder](c); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	closed = nil
+	err := c.Close(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "close failed") {
+		t.Errorf("Expected aggregated close error, got %v", err)
+	}
+	if got := strings.Join(closed, ","); got != "broken,dep" {
+		t.Errorf("Expected singletons to be closed once in reverse creation order, got %s", got)
+	}
+}
+
+type Greeter interface {
+	Greet() string
+}
+
+type baseGreeter struct{ name string }
+
+func (g *baseGreeter) Greet() string { return "hello " + g.name }
+
+type wrappedGreeter struct {
+	inner  Greeter
+	prefix string
+}
+
+func (g *wrappedGreeter) Greet() string { return g.prefix + "(" + g.inner.Greet() + ")" }
+
+type GreeterPrefix string
+
+func TestDecorators(t *testing.T) {
+	c := di.NewContainer()
+	di.ProvideService[Greeter](c, di.WithValue(Greeter(&baseGreeter{"value"})))
+	di.Provide(c, func() GreeterPrefix { return "trace" })
+	if err := di.Decorate(c, func(inner Greeter, prefix GreeterPrefix) Greeter {
+		return &wrappedGreeter{inner, string(prefix)}
+	}); err != nil {
+		t.Fatalf("Decorate failed: %v", err)
+	}
+	di.Decorate(c, func(inner Greeter) (Greeter, error) { return &wrappedGreeter{inner, "cache"}, nil })
+
+	created := 0
+	di.Provide(c, func() *ServiceA { created++; return &ServiceA{Val: created} }, di.WithScoped(), di.WithName("scoped"))
+	decorated := 0
+	di.DecorateNamed(c, "scoped", func(inner *ServiceA) *ServiceA {
+		decorated++
+		return &ServiceA{Val: inner.Val * 10}
+	})
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	g, _ := di.Get[Greeter](c)
+	if got := g.Greet(); got != "cache(trace(hello value))" {
+		t.Errorf("Expected decorators to apply in registration order, got %s", got)
+	}
+	if again, _ := di.Get[Greeter](c); again != g {
+		t.Error("Expected decorated singleton to be cached")
+	}
+
+	for i := 1; i <= 2; i++ {
+		scope := c.CreateScope()
+		a1, _ := di.GetNamed[*ServiceA](scope, "scoped")
+		a2, _ := di.GetNamed[*ServiceA](scope, "scoped")
+		if a1 != a2 || a1.Val != i*10 {
+			t.Errorf("Expected scoped service to be decorated once per scope, got %v and %v", a1, a2)
+		}
+	}
+	if decorated != 2 {
+		t.Errorf("Expected 2 decorations, got %d", decorated)
+	}
+
+	for _, info := range c.Services() {
+		if info.Key.Type == reflect.TypeFor[Greeter]() && (len(info.Dependencies) != 1 || info.Dependencies[0].Key.Type != reflect.TypeFor[GreeterPrefix]()) {
+			t.Errorf("Expected decorator dependencies to be reported, got %+v", info.Dependencies)
+		}
+	}
+
+	c = di.NewContainer()
+	if err := di.Decorate(c, func(inner Greeter) *baseGreeter { return nil }); err == nil {
+		t.Error("Expected mismatched decorator signature to fail")
+	}
+	di.Decorate(c, func(inner Greeter) Greeter { return inner })
+	if err := c.Build(); err == nil || !strings.Contains(err.Error(), "未注册") {
+		t.Errorf("Expected decorating an unregistered service to fail, got %v", err)
+	}
+}
+
+// forwardingCloser 转发 Close 的包装类型
+type forwardingCloser struct{ inner DisposeAlias }
+
+func (f *forwardingCloser) Close() error { return f.inner.Close() }
+
+func TestDecoratedDisposal(t *testing.T) {
+	var closed []string
+	c := di.NewContainer()
+	di.Provide(c, func() DisposeAlias { return &closeRecorder{"inner", &closed} })
+	di.Decorate(c, func(inner DisposeAlias) DisposeAlias { return &forwardingCloser{inner} })
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := strings.Join(closed, ","); got != "inner" {
+		t.Errorf("Expected the inner instance to be closed once through the wrapper, got %s", got)
+	}
+}
+
+func TestDecoratedValueDisposal(t *testing.T) {
+	var closed []string
+	c := di.NewContainer()
+	value := DisposeAlias(&closeRecorder{"value", &closed})
+	di.ProvideService[DisposeAlias](c, di.WithValue(value))
+	di.Decorate(c, func(inner DisposeAlias) DisposeAlias { return &forwardingCloser{inner} })
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(closed) != 0 {
+		t.Errorf("Expected the registrant to own a decorated value, got closed %v", closed)
+	}
+}
@@ -0,0 +1,96 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Disposable 需要在容器关闭或作用域释放时清理资源的服务。
+// 容器同样会释放实现了 io.Closer 的服务；两者都实现时只调用 Dispose。
+type Disposable interface {
+	Dispose(ctx context.Context) error
+}
+
+// disposer 按创建顺序记录需要释放的实例
+// 只记录容器创建的实例，以 WithValue 注册的现成实例由注册方负责释放。
+type disposer struct {
+	mu        sync.Mutex
+	instances []any
+	seen      map[any]struct{} // 已记录的指针实例，避免同一实例以多个类型注册时被重复释放
+}
+
+// track 记录需要释放的实例，不可释放的实例会被忽略
+func (d *disposer) track(instance any) {
+	switch instance.(type) {
+	case Disposable, io.Closer:
+	default:
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if reflect.ValueOf(instance).Kind() == reflect.Pointer {
+		if _, ok := d.seen[instance]; ok {
+			return
+		}
+		if d.seen == nil {
+			d.seen = make(map[any]struct{})
+		}
+		d.seen[instance] = struct{}{}
+	}
+	d.instances = append(d.instances, instance)
+}
+
+// tracks 判断实例是否已被记录
+func (d *disposer) tracks(instance any) bool {
+	if reflect.ValueOf(instance).Kind() != reflect.Pointer {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.seen[instance]
+	return ok
+}
+
+// dispose 按创建的相反顺序释放所有实例并清空记录，某个实例失败不会中断其他实例。
+// ctx 取消后放弃剩余实例，返回所有错误的组合。
+func (d *disposer) dispose(ctx context.Context) error {
+	d.mu.Lock()
+	instances := d.instances
+	d.instances = nil
+	d.seen = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for i := len(instances) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("di: 放弃释放剩余的 %d 个实例: %w", i+1, err))
+			break
+		}
+		if err := disposeInstance(ctx, instances[i]); err != nil {
+			errs = append(errs, fmt.Errorf("di: 释放 %T 失败: %w", instances[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// disposeInstance 释放单个实例，并将 panic 转换为错误
+func disposeInstance(ctx context.Context, instance any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	switch v := instance.(type) {
+	case Disposable:
+		return v.Dispose(ctx)
+	case io.Closer:
+		return v.Close()
+	}
+	return nil
+}
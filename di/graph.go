@@ -3,7 +3,6 @@ package di
 import (
 	"fmt"
 	"reflect"
-	"strings"
 )
 
 // graphBuilder 处理依赖图的构建和验证。
@@ -75,11 +74,35 @@ func (g *graphBuilder) buildOrder() ([]ServiceKey, error) {
 	return order, nil
 }
 
-// inspectDependencies 返回服务依赖的类型列表。
-// 它还会填充 ServiceDefinition.Schema。
+// inspectDependencies 返回服务及其装饰器依赖的类型列表。
+// 它还会填充 ServiceDefinition.Schema 与装饰器的 schema。
 func (g *graphBuilder) inspectDependencies(def *ServiceDefinition) ([]ServiceKey, error) {
+	deps, err := g.inspectService(def)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range def.decorators {
+		d.schema = &InjectionSchema{}
+		decoratorDeps, err := g.analyzeFunction(d.fn, d.schema)
+		if err != nil {
+			return nil, fmt.Errorf("装饰器 %T: %w", d.fn, err)
+		}
+		// 第一个参数是被装饰的实例本身，不是依赖
+		deps = append(deps, decoratorDeps[1:]...)
+	}
+	return deps, nil
+}
+
+// inspectService 返回服务本身依赖的类型列表，并填充 ServiceDefinition.Schema。
+func (g *graphBuilder) inspectService(def *ServiceDefinition) ([]ServiceKey, error) {
 	def.Schema = &InjectionSchema{}
 
+	// 情况 0: 结果对象 (di.Out) 的字段 - 依赖结果对象本身
+	if def.source != nil {
+		return []ServiceKey{{Type: def.source.Type, Name: def.source.Name}}, nil
+	}
+
 	// 情况 1: 值 - 仅当开启了 InjectFields 时才分析依赖
 	if def.IsValue {
 		if def.InjectFields {
@@ -113,10 +136,26 @@ func (g *graphBuilder) analyzeFunction(fn any, schema *InjectionSchema) ([]Servi
 	var deps []ServiceKey
 	for i := 0; i < fnType.NumIn(); i++ {
 		argType := fnType.In(i)
-		// 工厂函数参数暂不支持命名注入，默认为空名称
-		key := ServiceKey{Type: argType, Name: ""}
-		deps = append(deps, key)
 		schema.Args = append(schema.Args, argType)
+
+		// 普通参数按类型注入，名称为空
+		if !IsIn(argType) {
+			deps = append(deps, ServiceKey{Type: argType, Name: ""})
+			continue
+		}
+
+		// 参数对象按字段注入，可选字段不在图中强制执行
+		fields, err := inFields(argType)
+		if err != nil {
+			return nil, err
+		}
+		if schema.ArgFields == nil {
+			schema.ArgFields = make([][]FieldInjection, fnType.NumIn())
+		}
+		schema.ArgFields[i] = fields
+		for _, field := range fields {
+			deps = append(deps, g.fieldDependencies(field)...)
+		}
 	}
 	return deps, nil
 }
@@ -139,37 +178,32 @@ func (g *graphBuilder) analyzeStruct(typ reflect.Type, schema *InjectionSchema)
 			continue
 		}
 
-		// 解析 tag: "name,option1,option2"
-		parts := strings.Split(tagValue, ",")
-		name := strings.TrimSpace(parts[0])
-		isOptional := false
-
-		// 处理 "di:?" 或 "di:optional" 的情况，此时 name 应为空
-		if name == "?" || name == "optional" {
-			name = ""
-			isOptional = true
-		}
-
-		for _, part := range parts[1:] {
-			part = strings.TrimSpace(part)
-			if part == "optional" || part == "?" {
-				isOptional = true
-			}
+		// 解析 tag: "name,option1,option2" 或 "group:组名"
+		injection, err := newFieldInjection(typ, i, field, tagValue)
+		if err != nil {
+			return nil, err
 		}
 
 		// 记录字段注入元数据
-		schema.Fields = append(schema.Fields, FieldInjection{
-			Index:       i,
-			Name:        field.Name,
-			Type:        field.Type,
-			Optional:    isOptional,
-			ServiceName: name,
-		})
-
-		if isOptional {
-			continue // 不在图中强制执行可选依赖
-		}
-		deps = append(deps, ServiceKey{Type: field.Type, Name: name})
+		schema.Fields = append(schema.Fields, injection)
+		deps = append(deps, g.fieldDependencies(injection)...)
 	}
 	return deps, nil
 }
+
+// fieldDependencies 返回字段在图中的依赖：组字段依赖组内的所有成员，可选字段不在图中强制执行
+func (g *graphBuilder) fieldDependencies(field FieldInjection) []ServiceKey {
+	if field.Group != "" {
+		var deps []ServiceKey
+		for key, def := range g.definitions {
+			if def.Group == field.Group {
+				deps = append(deps, key)
+			}
+		}
+		return deps
+	}
+	if field.Optional {
+		return nil
+	}
+	return []ServiceKey{{Type: field.Type, Name: field.ServiceName}}
+}
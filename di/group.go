@@ -0,0 +1,45 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// InGroup 将服务加入组，可通过 GetGroup、`di:"group:名称"` 标签或参数对象以切片形式注入组内的所有服务。
+// 组成员按注册顺序排列，各自保持自己的作用域；同一类型可以多次加入同一组，
+// 未指定名称的成员会被分配名称 "组名[序号]"，因此不会与同类型的普通注册冲突。
+func InGroup(group string) Option {
+	return func(s *ServiceDefinition) {
+		s.Group = group
+	}
+}
+
+// GetGroup 按注册顺序解析组内的所有服务。
+// 组不存在时返回空切片；成员不能赋值给 T 时返回错误。
+func GetGroup[T any](c Container, group string) ([]T, error) {
+	val, err := c.GetGroup(reflect.TypeFor[T](), group)
+	if err != nil {
+		return nil, err
+	}
+	return val.([]T), nil
+}
+
+// resolveGroup 从 c 中解析组成员，返回元素类型为 typ 的切片。
+func resolveGroup(c Container, members []ServiceKey, typ reflect.Type, group string) (any, error) {
+	slice := reflect.MakeSlice(reflect.SliceOf(typ), 0, len(members))
+	for _, key := range members {
+		if !key.Type.AssignableTo(typ) {
+			return nil, fmt.Errorf("di: 组 %s 的成员 %v 不能赋值给 %v", group, key, typ)
+		}
+		member, err := c.GetNamed(key.Type, key.Name)
+		if err != nil {
+			return nil, fmt.Errorf("di: 解析组 %s 的成员 %v 失败: %w", group, key, err)
+		}
+		val := reflect.New(typ).Elem()
+		if member != nil {
+			val.Set(reflect.ValueOf(member))
+		}
+		slice = reflect.Append(slice, val)
+	}
+	return slice.Interface(), nil
+}
@@ -0,0 +1,123 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// In 参数对象标记。
+// 嵌入 di.In 的结构体可以作为构造函数、Invoke、cron 任务与事件处理器的参数，
+// 其导出字段会被逐一注入，字段的 di 标签与结构体字段注入相同（"name,optional" 或 "group:组名"），没有标签的字段按类型注入：
+//
+//	type ReportParams struct {
+//	    di.In
+//	    DB     *gorm.DB       `di:"reporting"`
+//	    Cache  *redis.Client  `di:"cache,optional"`
+//	    Checks []health.Check `di:"group:checks"`
+//	}
+//
+//	func NewReportService(p ReportParams) *ReportService
+//
+// 可选依赖缺失时字段保持零值。
+type In struct{}
+
+var inType = reflect.TypeFor[In]()
+
+// IsIn 判断类型是否为参数对象（嵌入了 di.In 的结构体）。
+func IsIn(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := range typ.NumField() {
+		if field := typ.Field(i); field.Anonymous && field.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveParam 从容器解析一个函数参数。
+// 参数对象按字段解析，其他类型按类型解析（名称为空）。
+func ResolveParam(c Container, typ reflect.Type) (reflect.Value, error) {
+	if !IsIn(typ) {
+		val, err := c.Get(typ)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(val), nil
+	}
+
+	fields, err := inFields(typ)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return newIn(c, typ, fields)
+}
+
+// inFields 返回参数对象中需要注入的字段。
+func inFields(typ reflect.Type) ([]FieldInjection, error) {
+	var fields []FieldInjection
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type == inType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("di: 参数对象 %v 的字段 %s 未导出", typ, field.Name)
+		}
+		injection, err := newFieldInjection(typ, i, field, field.Tag.Get("di"))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, injection)
+	}
+	return fields, nil
+}
+
+// newIn 创建参数对象并注入其字段。
+func newIn(c Container, typ reflect.Type, fields []FieldInjection) (reflect.Value, error) {
+	val := reflect.New(typ).Elem()
+	if err := newResolver().injectFields(c, val, &InjectionSchema{Fields: fields}); err != nil {
+		return reflect.Value{}, fmt.Errorf("参数对象 %v: %w", typ, err)
+	}
+	return val, nil
+}
+
+// parseTag 解析 di 标签 "name,option1,option2"，返回服务名称、组名与是否可选。
+// "?" 与 "optional" 既可以作为选项，也可以单独使用（此时名称为空）；
+// "group:组名" 表示组而不是服务名称。
+func parseTag(tag string) (name, group string, optional bool) {
+	parts := strings.Split(tag, ",")
+	name = strings.TrimSpace(parts[0])
+	if name == "?" || name == "optional" {
+		name = ""
+		optional = true
+	}
+	if g, ok := strings.CutPrefix(name, "group:"); ok {
+		name, group = "", g
+	}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "optional" || part == "?" {
+			optional = true
+		}
+	}
+	return name, group, optional
+}
+
+// newFieldInjection 根据结构体字段及其 di 标签生成注入元数据，组字段必须为切片
+func newFieldInjection(owner reflect.Type, index int, field reflect.StructField, tag string) (FieldInjection, error) {
+	name, group, optional := parseTag(tag)
+	if group != "" && field.Type.Kind() != reflect.Slice {
+		return FieldInjection{}, fmt.Errorf("di: %v 的字段 %s 注入组 %s，类型必须为切片", owner, field.Name, group)
+	}
+	return FieldInjection{
+		Index:       index,
+		Name:        field.Name,
+		Type:        field.Type,
+		Optional:    optional,
+		ServiceName: name,
+		Group:       group,
+	}, nil
+}
@@ -1,6 +1,9 @@
 package di_test
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/gocrud/app/di"
@@ -162,3 +165,198 @@ func TestNamedResolve(t *testing.T) {
 		t.Error("Expected error for missing named service")
 	}
 }
+
+func TestTaggedServices(t *testing.T) {
+	c := di.NewContainer()
+
+	di.ProvideService[*Database](c, di.WithName("master"), di.WithValue(&Database{DSN: "master_dsn"}), di.WithTag("db"))
+	di.ProvideService[*ServiceWithNamedDB](c)
+	di.ProvideService[*Database](c, di.WithName("slave"), di.WithValue(&Database{DSN: "slave_dsn"}), di.WithTag("db"))
+
+	keys := c.Tagged("db")
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 tagged services, got %d", len(keys))
+	}
+	if keys[0].Name != "master" || keys[1].Name != "slave" {
+		t.Errorf("Expected registration order [master slave], got [%s %s]", keys[0].Name, keys[1].Name)
+	}
+	if len(c.Tagged("missing")) != 0 {
+		t.Error("Expected no services for unknown tag")
+	}
+}
+
+type ReportParams struct {
+	di.In
+	Primary *Database `di:"master"`
+	Replica *Database `di:"slave"`
+	Cache   *Database `di:"cache,optional"`
+}
+
+type ReportService struct {
+	params ReportParams
+}
+
+func TestParameterObjects(t *testing.T) {
+	c := di.NewContainer()
+	di.ProvideService[*Database](c, di.WithName("master"), di.WithValue(&Database{DSN: "master_dsn"}))
+	di.ProvideService[*Database](c, di.WithName("slave"), di.WithValue(&Database{DSN: "slave_dsn"}))
+	if _, err := di.Provide(c, func(p ReportParams) *ReportService { return &ReportService{params: p} }); err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	svc, err := di.Get[*ReportService](c)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if svc.params.Primary.DSN != "master_dsn" || svc.params.Replica.DSN != "slave_dsn" || svc.params.Cache != nil {
+		t.Errorf("Unexpected parameter object %+v", svc.params)
+	}
+
+	// 依赖图记录命名依赖
+	var deps []string
+	for _, info := range c.Services() {
+		if info.Key.Type.String() == "*di_test.ReportService" {
+			for _, dep := range info.Dependencies {
+				deps = append(deps, fmt.Sprintf("%s:%t", dep.Key, dep.Optional))
+			}
+		}
+	}
+	if got := strings.Join(deps, " "); got != "*di_test.Database (name=master):false *di_test.Database (name=slave):false *di_test.Database (name=cache):true" {
+		t.Errorf("Unexpected dependencies %s", got)
+	}
+
+	err = di.Invoke(c, func(p ReportParams) {
+		if p.Replica.DSN != "slave_dsn" {
+			t.Errorf("Expected slave DSN, got %s", p.Replica.DSN)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+
+	// 缺失的必需命名依赖在构建时即可发现
+	c = di.NewContainer()
+	_, _ = di.Provide(c, func(p ReportParams) *ReportService { return &ReportService{params: p} })
+	if err := c.Build(); err == nil || !strings.Contains(err.Error(), "name=master") {
+		t.Errorf("Expected missing named dependency error, got %v", err)
+	}
+}
+
+type ClientResult struct {
+	di.Out
+	Primary *Database
+	Backup  *Database `di:"backup"`
+}
+
+type ClientConsumer struct {
+	Backup *Database `di:"backup"`
+}
+
+func TestResultObjects(t *testing.T) {
+	c := di.NewContainer()
+	calls := 0
+	_, err := di.Provide(c, func() (ClientResult, error) {
+		calls++
+		return ClientResult{Primary: &Database{DSN: "primary"}, Backup: &Database{DSN: "backup"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+	di.ProvideService[*ClientConsumer](c)
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	primary, err := di.Get[*Database](c)
+	if err != nil || primary.DSN != "primary" {
+		t.Errorf("Expected primary database, got %v (%v)", primary, err)
+	}
+	consumer, err := di.Get[*ClientConsumer](c)
+	if err != nil || consumer.Backup.DSN != "backup" {
+		t.Errorf("Expected backup database to be injected, got %v (%v)", consumer, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected constructor to be called once, got %d", calls)
+	}
+
+	for _, info := range c.Services() {
+		if info.Key.Name == "backup" && (len(info.Dependencies) != 1 || info.Dependencies[0].Key.Type.String() != "di_test.ClientResult") {
+			t.Errorf("Expected field service to depend on the result object, got %+v", info.Dependencies)
+		}
+	}
+}
+
+type Controller interface {
+	Route() string
+}
+
+type routeController struct {
+	route string
+}
+
+func (r *routeController) Route() string { return r.route }
+
+type ControllerRegistry struct {
+	Controllers []Controller `di:"group:controllers"`
+}
+
+type RouterParams struct {
+	di.In
+	Controllers []Controller `di:"group:controllers"`
+}
+
+func TestValueGroups(t *testing.T) {
+	c := di.NewContainer()
+	_, _ = di.Provide(c, func() Controller { return &routeController{route: "/users"} }, di.InGroup("controllers"))
+	_, _ = di.Provide(c, func() Controller { return &routeController{route: "/orders"} }, di.InGroup("controllers"))
+	_, _ = di.Provide(c, func() Controller { return &routeController{route: "/scoped"} }, di.InGroup("controllers"), di.WithScoped())
+	_, _ = di.Provide(c, func() Controller { return &routeController{route: "/default"} })
+	di.ProvideService[*ControllerRegistry](c, di.WithScoped())
+	var routes []string
+	_, _ = di.Provide(c, func(p RouterParams) *Database {
+		for _, ctrl := range p.Controllers {
+			routes = append(routes, ctrl.Route())
+		}
+		return &Database{}
+	}, di.WithScoped())
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if _, err := di.GetGroup[Controller](c, "controllers"); err == nil {
+		t.Error("Expected scoped group member to fail from root container")
+	}
+	if ctrl, err := di.Get[Controller](c); err != nil || ctrl.Route() != "/default" {
+		t.Errorf("Expected ungrouped registration to resolve by type, got %v (%v)", ctrl, err)
+	}
+
+	scope := c.CreateScope()
+	defer scope.Dispose(context.Background())
+	group, err := di.GetGroup[Controller](scope, "controllers")
+	if err != nil {
+		t.Fatalf("GetGroup failed: %v", err)
+	}
+	var got []string
+	for _, ctrl := range group {
+		got = append(got, ctrl.Route())
+	}
+	if want := "/users,/orders,/scoped"; strings.Join(got, ",") != want {
+		t.Errorf("Expected %s in registration order, got %v", want, got)
+	}
+
+	registry, err := di.Get[*ControllerRegistry](scope)
+	if err != nil || len(registry.Controllers) != 3 || registry.Controllers[0] != group[0] {
+		t.Errorf("Expected group field to be injected with singleton members, got %+v (%v)", registry, err)
+	}
+	if _, err := di.Get[*Database](scope); err != nil || strings.Join(routes, ",") != strings.Join(got, ",") {
+		t.Errorf("Expected group parameter object to be injected, got %v (%v)", routes, err)
+	}
+
+	empty, err := di.GetGroup[Controller](c, "missing")
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected empty group, got %v (%v)", empty, err)
+	}
+}
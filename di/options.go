@@ -67,3 +67,20 @@ func WithFields() Option {
 		s.InjectFields = true
 	}
 }
+
+// TagHosted 托管服务标签。
+// 带有该标签的服务会在应用启动时被自动发现，并作为 core.HostedService 启动和停止。
+const TagHosted = "hosted"
+
+// WithTag 为服务添加标签，可通过 Container.Tagged 按标签查找服务。
+func WithTag(tags ...string) Option {
+	return func(s *ServiceDefinition) {
+		s.Tags = append(s.Tags, tags...)
+	}
+}
+
+// AsHosted 将服务标记为托管服务（等价于 WithTag(TagHosted)）。
+// 服务必须实现 core.HostedService 接口。
+func AsHosted() Option {
+	return WithTag(TagHosted)
+}
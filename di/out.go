@@ -0,0 +1,81 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Out 结果对象标记。
+// 构造函数返回嵌入 di.Out 的结构体时，结构体的每个导出字段都会注册为独立的服务，
+// 字段的 di 标签指定服务名称或所在的组 ("group:组名")；所有字段共享同一次构造函数调用：
+//
+//	type ClientResult struct {
+//	    di.Out
+//	    Client  *Client
+//	    Metrics *Collector `di:"client"`
+//	}
+//
+//	func NewClient(cfg *Config) (ClientResult, error)
+//
+// 注册选项（作用域、名称、标签）作用于结果对象本身，字段服务沿用结果对象的作用域；
+// 瞬态作用域下每次解析字段都会重新调用构造函数。
+type Out struct{}
+
+var outType = reflect.TypeFor[Out]()
+
+// IsOut 判断类型是否为结果对象（嵌入了 di.Out 的结构体）。
+func IsOut(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := range typ.NumField() {
+		if field := typ.Field(i); field.Anonymous && field.Type == outType {
+			return true
+		}
+	}
+	return false
+}
+
+// provideOut 注册结果对象及其每个字段对应的服务。
+func provideOut(c Container, result *ServiceDefinition) error {
+	typ := result.Type
+
+	var fields []*ServiceDefinition
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type == outType {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("di: 结果对象 %v 的字段 %s 未导出", typ, field.Name)
+		}
+		name, group, _ := parseTag(field.Tag.Get("di"))
+		fields = append(fields, &ServiceDefinition{
+			Type:        field.Type,
+			Name:        name,
+			Group:       group,
+			Scope:       result.Scope,
+			source:      result,
+			sourceField: i,
+		})
+	}
+
+	if err := c.Add(result); err != nil {
+		return err
+	}
+	for _, def := range fields {
+		if err := c.Add(def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveOutField 解析结果对象，并取出字段对应的服务。
+func resolveOutField(c Container, def *ServiceDefinition) (any, error) {
+	result, err := c.GetNamed(def.source.Type, def.source.Name)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(result).Field(def.sourceField).Interface(), nil
+}
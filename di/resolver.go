@@ -11,9 +11,22 @@ func newResolver() *resolver {
 	return &resolver{}
 }
 
-// createInstance 创建 def 描述的服务的新实例。
-// 它使用提供的容器 c 递归解析依赖项。
+// createInstance 创建 def 描述的服务的新实例，并应用其装饰器。
+// 它使用提供的容器 c 递归解析依赖项。返回的是最终实例，调用方只需记录它以便释放。
 func (r *resolver) createInstance(c Container, def *ServiceDefinition) (any, error) {
+	instance, err := r.construct(c, def)
+	if err != nil || len(def.decorators) == 0 {
+		return instance, err
+	}
+	return r.decorate(c, def, instance)
+}
+
+// construct 根据服务定义创建未装饰的实例。
+func (r *resolver) construct(c Container, def *ServiceDefinition) (any, error) {
+	if def.source != nil {
+		return resolveOutField(c, def)
+	}
+
 	if def.IsValue {
 		// 如果标记了 InjectFields 并且有 schema，则尝试注入字段
 		if def.InjectFields && def.Schema != nil {
@@ -45,21 +58,42 @@ func (r *resolver) createInstance(c Container, def *ServiceDefinition) (any, err
 // invokeFunction 调用工厂或构造函数。
 // 它使用预计算的 schema 将依赖项注入函数参数。
 func (r *resolver) invokeFunction(c Container, fn any, schema *InjectionSchema) (any, error) {
-	fnVal := reflect.ValueOf(fn)
 	// 使用 schema 获取参数类型而不是反射
-	argTypes := schema.Args
+	args := make([]reflect.Value, len(schema.Args))
+	for i := range args {
+		arg, err := r.resolveArg(c, schema, i)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return callFunction(reflect.ValueOf(fn), args)
+}
+
+// resolveArg 解析函数的第 i 个参数。
+func (r *resolver) resolveArg(c Container, schema *InjectionSchema, i int) (reflect.Value, error) {
+	argType := schema.Args[i]
 
-	args := make([]reflect.Value, len(argTypes))
-	for i, argType := range argTypes {
-		// 工厂函数目前不支持命名注入，因此使用空名称
-		argVal, err := c.GetNamed(argType, "")
+	// 参数对象 (di.In) 按字段注入，支持命名与可选依赖
+	if i < len(schema.ArgFields) && schema.ArgFields[i] != nil {
+		in, err := newIn(c, argType, schema.ArgFields[i])
 		if err != nil {
-			return nil, fmt.Errorf("参数 %d: %w", i, err)
+			return reflect.Value{}, fmt.Errorf("参数 %d: %w", i, err)
 		}
-		// 处理接口赋值
-		args[i] = reflect.ValueOf(argVal)
+		return in, nil
+	}
+
+	// 普通参数按类型注入，名称为空
+	argVal, err := c.GetNamed(argType, "")
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("参数 %d: %w", i, err)
 	}
+	// 处理接口赋值
+	return reflect.ValueOf(argVal), nil
+}
 
+// callFunction 调用函数，返回第一个返回值；最后一个返回值为非 nil 的 error 时返回该错误。
+func callFunction(fnVal reflect.Value, args []reflect.Value) (any, error) {
 	results := fnVal.Call(args)
 
 	if len(results) == 0 {
@@ -113,6 +147,16 @@ func (r *resolver) createStruct(c Container, def *ServiceDefinition) (any, error
 func (r *resolver) injectFields(c Container, structVal reflect.Value, schema *InjectionSchema) error {
 	// 使用预计算 schema 仅迭代需要注入的字段
 	for _, fieldInfo := range schema.Fields {
+		// 组字段注入组内所有服务组成的切片
+		if fieldInfo.Group != "" {
+			group, err := c.GetGroup(fieldInfo.Type.Elem(), fieldInfo.Group)
+			if err != nil {
+				return fmt.Errorf("字段 %s: %w", fieldInfo.Name, err)
+			}
+			structVal.Field(fieldInfo.Index).Set(reflect.ValueOf(group))
+			continue
+		}
+
 		// 解析依赖
 		depVal, err := c.GetNamed(fieldInfo.Type, fieldInfo.ServiceName)
 		if err != nil {
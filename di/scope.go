@@ -1,6 +1,8 @@
 package di
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -10,18 +12,26 @@ import (
 // Scope 表示作用域生命周期上下文。
 type Scope interface {
 	Container
-	// Dispose 释放与作用域关联的资源。
-	Dispose()
+	// Dispose 按创建的相反顺序释放作用域创建的作用域实例与瞬态实例（Disposable 或 io.Closer），
+	// 返回所有释放错误的组合。单例由根容器的 Close 释放。
+	Dispose(ctx context.Context) error
 }
 
+// ErrScopeDisposed 作用域已经释放，不能再解析服务
+var ErrScopeDisposed = errors.New("di: 作用域已释放")
+
 type scopeEntry struct {
 	val atomic.Value // 存储实例（如果尚未创建则为 nil）
 	mu  sync.Mutex   // 用于创建此特定实例的锁
 }
 
 type scope struct {
-	parent  *container
-	entries []scopeEntry // 按 ServiceDefinition.ID 索引的数组
+	parent   *container
+	disposer disposer // 记录需要在 Dispose 时释放的实例
+
+	mu       sync.Mutex
+	entries  []scopeEntry // 按 ServiceDefinition.ID 索引的数组，释放后为 nil
+	disposed bool
 }
 
 func newScope(parent *container) *scope {
@@ -36,6 +46,10 @@ func (s *scope) Add(def *ServiceDefinition) error {
 	return fmt.Errorf("di: 无法在作用域上注册服务")
 }
 
+func (s *scope) addDecorator(key ServiceKey, fn any) error {
+	return fmt.Errorf("di: 无法在作用域上注册装饰器")
+}
+
 func (s *scope) Build() error {
 	return nil // 作用域已基于父容器构建
 }
@@ -44,6 +58,18 @@ func (s *scope) CreateScope() Scope {
 	return s.parent.CreateScope()
 }
 
+func (s *scope) Tagged(tag string) []ServiceKey {
+	return s.parent.Tagged(tag)
+}
+
+func (s *scope) GetGroup(typ reflect.Type, group string) (any, error) {
+	return resolveGroup(s, s.parent.groupMembers(group), typ, group)
+}
+
+func (s *scope) Services() []ServiceInfo {
+	return s.parent.Services()
+}
+
 func (s *scope) Get(typ reflect.Type) (any, error) {
 	return s.GetNamed(typ, "")
 }
@@ -61,24 +87,40 @@ func (s *scope) GetNamed(typ reflect.Type, name string) (any, error) {
 	}
 
 	// 2. 处理不同作用域
-	switch def.Scope {
-	case ScopeSingleton:
+	if def.Scope == ScopeSingleton {
 		return s.parent.GetNamed(typ, name)
+	}
+
+	// 取得条目切片的快照，Dispose 在此之后将 s.entries 置空也不影响本次解析
+	s.mu.Lock()
+	entries, disposed := s.entries, s.disposed
+	s.mu.Unlock()
+	if disposed {
+		return nil, ErrScopeDisposed
+	}
 
+	switch def.Scope {
 	case ScopeTransient:
 		// 使用此作用域作为容器创建新实例（用于依赖项）
-		return s.parent.resolver.createInstance(s, def)
+		instance, err := s.parent.resolver.createInstance(s, def)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.track(instance); err != nil {
+			return nil, err
+		}
+		return instance, nil
 
 	case ScopeScoped:
 		// 使用 ID 进行 O(1) 数组访问
-		if def.ID < 0 || def.ID >= len(s.entries) {
+		if def.ID < 0 || def.ID >= len(entries) {
 			// 如果 ID 分配正确，这不应发生
 			return nil, fmt.Errorf("di: 内部错误，无效的服务 ID %d", def.ID)
 		}
 
 		// 我们获取切片中条目的指针。
 		// 由于切片大小在创建后是固定的，此指针是稳定的。
-		entry := &s.entries[def.ID]
+		entry := &entries[def.ID]
 
 		// 快速路径：检查是否已创建
 		if val := entry.val.Load(); val != nil {
@@ -100,6 +142,9 @@ func (s *scope) GetNamed(typ reflect.Type, name string) (any, error) {
 			return nil, err
 		}
 
+		if err := s.track(instance); err != nil {
+			return nil, err
+		}
 		entry.val.Store(instance)
 		return instance, nil
 	}
@@ -107,17 +152,43 @@ func (s *scope) GetNamed(typ reflect.Type, name string) (any, error) {
 	return nil, fmt.Errorf("di: 未知作用域 %v", def.Scope)
 }
 
-func (s *scope) Dispose() {
-	// 释放引用以允许 GC
-	// 我们不能在不锁定的情况下轻松地将切片调整为 0，
-	// 但如果需要，我们可以清零条目。
-	// 但是，由于 scope 通常是整体丢弃的，只需让它超出范围就足够了。
-	// 如果需要显式清理（例如对实例调用 Close()），将在此处进行。
-	// 目前，我们只清除切片以帮助 GC，如果 scope 对象本身保持活动状态（这很少见）。
-	for i := range s.entries {
-		s.entries[i].val.Store(nil)
+// Dispose 释放作用域，重复调用不做任何操作；释放后解析服务返回 ErrScopeDisposed。
+func (s *scope) Dispose(ctx context.Context) error {
+	s.mu.Lock()
+	if s.disposed {
+		s.mu.Unlock()
+		return nil
 	}
+	s.disposed = true
+	// 释放引用以允许 GC，注意 atomic.Value 不能存储 nil，因此直接丢弃整个切片。
 	s.entries = nil
+	s.mu.Unlock()
+
+	return s.disposer.dispose(ctx)
+}
+
+// Close 等同于 Dispose。
+func (s *scope) Close(ctx context.Context) error {
+	return s.Dispose(ctx)
+}
+
+// track 记录作用域创建的实例，跳过根容器已经负责释放的单例（例如以接口形式返回的单例）
+// 实例创建期间作用域被释放时，立即释放该实例并返回 ErrScopeDisposed。
+func (s *scope) track(instance any) error {
+	if s.parent.disposer.tracks(instance) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.disposed {
+		if err := disposeInstance(context.Background(), instance); err != nil {
+			return errors.Join(ErrScopeDisposed, fmt.Errorf("di: 释放 %T 失败: %w", instance, err))
+		}
+		return ErrScopeDisposed
+	}
+	s.disposer.track(instance)
+	return nil
 }
 
 // serviceCount 委托给父容器
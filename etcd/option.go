@@ -6,6 +6,7 @@ import (
 
 	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -29,7 +30,7 @@ func WithClient(name string, opts ...func(*EtcdClientOptions)) BuilderOption {
 
 // New 启用 Etcd 能力
 func New(opts ...BuilderOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("etcd", func(rt *core.Runtime) error {
 		builder := NewBuilder()
 		for _, opt := range opts {
 			opt(builder)
@@ -50,17 +51,31 @@ func New(opts ...BuilderOption) core.Option {
 		}
 
 		// 注册各个客户端
+		registry := health.RegistryFrom(rt)
 		var defaultRegErr error
 		factory.Each(func(name string, client *clientv3.Client) {
 			if err := rt.Provide(client, di.WithName(name), di.WithValue(client)); err != nil {
 				defaultRegErr = err
 			}
-			
+
 			if name == "default" {
 				if err := rt.Provide(client, di.WithValue(client)); err != nil {
 					defaultRegErr = err
 				}
 			}
+
+			// 注册健康检查：任一节点可用即视为健康
+			if err := registry.RegisterFunc("etcd:"+name, func(ctx context.Context) error {
+				var err error
+				for _, endpoint := range client.Endpoints() {
+					if _, err = client.Status(ctx, endpoint); err == nil {
+						return nil
+					}
+				}
+				return err
+			}); err != nil {
+				defaultRegErr = err
+			}
 		})
 
 		if defaultRegErr != nil {
@@ -71,8 +86,8 @@ func New(opts ...BuilderOption) core.Option {
 		rt.Lifecycle.OnStop(func(ctx context.Context) error {
 			fmt.Println("Closing etcd clients")
 			return factory.Close()
-		})
+		}, core.WithHookName("etcd"), core.InStage(core.StageInfrastructure))
 
 		return nil
-	}
+	}))
 }
@@ -0,0 +1,297 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// HandlerOption 配置单个处理器
+type HandlerOption func(*subscription)
+
+// Async 在后台工作池中执行处理器，Publish 不等待其完成
+// 异步处理器的错误通过 Runtime.ErrorHandler 报告。
+func Async() HandlerOption {
+	return func(s *subscription) {
+		s.async = true
+	}
+}
+
+// WithName 设置处理器名称，用于错误信息
+func WithName(name string) HandlerOption {
+	return func(s *subscription) {
+		s.name = name
+	}
+}
+
+// subscription 一个已注册的处理器
+type subscription struct {
+	name  string
+	async bool
+	// scoped 为 true 时每次分发创建新的 DI 作用域
+	scoped bool
+	invoke func(ctx context.Context, scope di.Container, evt any) error
+}
+
+// job 等待异步执行的分发
+type job struct {
+	ctx context.Context
+	sub *subscription
+	evt any
+}
+
+// Bus 进程内事件总线
+// 同步处理器在 Publish 的调用方 Goroutine 中按注册顺序执行；
+// 异步处理器进入有界队列，由固定数量的工作协程执行，队列已满时 Publish 阻塞直到有空位或 ctx 取消。
+type Bus struct {
+	mu        sync.RWMutex
+	handlers  map[reflect.Type][]*subscription
+	container di.Container
+	policy    ErrorPolicy
+	report    func(error)
+
+	workers int
+	queue   chan job
+	wg      sync.WaitGroup
+	stopped bool
+	// done 在停止时关闭，唤醒等待队列空位的发布者
+	done chan struct{}
+	// senders 正在入队的发布者，全部返回后才能关闭队列
+	senders sync.WaitGroup
+}
+
+// newBus 创建事件总线
+func newBus(container di.Container, workers, queueSize int, policy ErrorPolicy, report func(error)) *Bus {
+	return &Bus{
+		handlers:  make(map[reflect.Type][]*subscription),
+		container: container,
+		policy:    policy,
+		report:    report,
+		workers:   workers,
+		queue:     make(chan job, queueSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Subscribe 注册函数处理器
+// handler 的参数中 context.Context 与事件类型 T 由总线传入，其余参数从 DI 容器解析
+// （每次分发使用新的作用域）；最后一个返回值可以是 error。
+//
+// 示例：
+//
+//	events.Subscribe(bus, func(ctx context.Context, evt OrderPlaced, mailer *Mailer) error {
+//	    return mailer.SendReceipt(ctx, evt.OrderID)
+//	})
+func Subscribe[T any](b *Bus, handler any, opts ...HandlerOption) error {
+	eventType := reflect.TypeFor[T]()
+	fn := reflect.ValueOf(handler)
+	if fn.Kind() != reflect.Func {
+		return fmt.Errorf("events: handler for %v must be a function, got %T", eventType, handler)
+	}
+	fnType := fn.Type()
+
+	hasEvent, scoped := false, false
+	for i := range fnType.NumIn() {
+		switch fnType.In(i) {
+		case eventType:
+			hasEvent = true
+		case contextType:
+		default:
+			scoped = true
+		}
+	}
+	if !hasEvent {
+		return fmt.Errorf("events: handler %v does not accept event %v", fnType, eventType)
+	}
+
+	sub := &subscription{
+		name:   runtime.FuncForPC(fn.Pointer()).Name(),
+		scoped: scoped,
+		invoke: func(ctx context.Context, scope di.Container, evt any) error {
+			args := make([]reflect.Value, fnType.NumIn())
+			for i := range args {
+				switch paramType := fnType.In(i); paramType {
+				case eventType:
+					args[i] = reflect.ValueOf(evt)
+				case contextType:
+					args[i] = reflect.ValueOf(ctx)
+				default:
+					instance, err := di.ResolveParam(scope, paramType)
+					if err != nil {
+						return fmt.Errorf("failed to resolve parameter %d (%v): %w", i, paramType, err)
+					}
+					args[i] = instance
+				}
+			}
+
+			results := fn.Call(args)
+			if n := len(results); n > 0 {
+				if err, ok := results[n-1].Interface().(error); ok {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	b.add(eventType, sub, opts)
+	return nil
+}
+
+// SubscribeService 注册服务处理器
+// H 需要已注册到 DI 容器，每次分发时从新的作用域中解析 H 并调用其 Handle 方法。
+func SubscribeService[T any, H Handler[T]](b *Bus, opts ...HandlerOption) {
+	handlerType := reflect.TypeFor[H]()
+	sub := &subscription{
+		name:   handlerType.String(),
+		scoped: true,
+		invoke: func(ctx context.Context, scope di.Container, evt any) error {
+			handler, err := di.Get[H](scope)
+			if err != nil {
+				return err
+			}
+			return handler.Handle(ctx, evt.(T))
+		},
+	}
+	b.add(reflect.TypeFor[T](), sub, opts)
+}
+
+// add 记录处理器
+func (b *Bus) add(eventType reflect.Type, sub *subscription, opts []HandlerOption) {
+	for _, opt := range opts {
+		opt(sub)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], sub)
+}
+
+// Publish 发布事件
+// 同步处理器执行完成后返回，错误按总线的 ErrorPolicy 处理；异步处理器入队后即返回。
+func Publish[T any](ctx context.Context, b *Bus, evt T) error {
+	return b.publish(ctx, reflect.TypeFor[T](), evt)
+}
+
+// publish 按注册顺序分发事件
+func (b *Bus) publish(ctx context.Context, eventType reflect.Type, evt any) error {
+	b.mu.RLock()
+	subs := b.handlers[eventType]
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if sub.async {
+			if err := b.enqueue(ctx, job{ctx: context.WithoutCancel(ctx), sub: sub, evt: evt}); err != nil {
+				errs = append(errs, fmt.Errorf("events: failed to queue %v for handler %s: %w", eventType, sub.name, err))
+			}
+			continue
+		}
+
+		if err := b.dispatch(ctx, sub, evt); err != nil {
+			errs = append(errs, fmt.Errorf("events: handler %s failed for %v: %w", sub.name, eventType, err))
+			if b.policy == StopOnError {
+				break
+			}
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil && b.policy == ReportErrors {
+		b.report(err)
+		return nil
+	}
+	return err
+}
+
+// enqueue 将异步分发放入队列，队列已满时等待
+// 等待期间不持有锁，总线停止时立即返回 ErrStopped。
+func (b *Bus) enqueue(ctx context.Context, j job) error {
+	b.mu.RLock()
+	if b.stopped {
+		b.mu.RUnlock()
+		return ErrStopped
+	}
+	b.senders.Add(1)
+	b.mu.RUnlock()
+	defer b.senders.Done()
+
+	select {
+	case b.queue <- j:
+		return nil
+	case <-b.done:
+		return ErrStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dispatch 执行单个处理器，需要时创建新的作用域，并将 panic 转换为 core.PanicError
+func (b *Bus) dispatch(ctx context.Context, sub *subscription, evt any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &core.PanicError{Service: "event handler " + sub.name, Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	if !sub.scoped {
+		return sub.invoke(ctx, nil, evt)
+	}
+	scope := b.container.CreateScope()
+	defer func() {
+		// 处理器的上下文可能已经取消，释放作用域实例不继承其取消信号
+		if disposeErr := scope.Dispose(context.WithoutCancel(ctx)); disposeErr != nil {
+			err = errors.Join(err, disposeErr)
+		}
+	}()
+	return sub.invoke(ctx, scope, evt)
+}
+
+// start 启动工作协程
+func (b *Bus) start() {
+	for range b.workers {
+		b.wg.Go(func() {
+			for j := range b.queue {
+				if err := b.dispatch(j.ctx, j.sub, j.evt); err != nil {
+					b.report(fmt.Errorf("events: async handler %s failed: %w", j.sub.name, err))
+				}
+			}
+		})
+	}
+}
+
+// stop 停止接受异步事件，并等待队列中的事件处理完成
+func (b *Bus) stop(ctx context.Context) error {
+	b.mu.Lock()
+	stopping := !b.stopped
+	b.stopped = true
+	b.mu.Unlock()
+
+	if stopping {
+		// 此后不会有新的发布者，唤醒等待中的发布者并在它们返回后关闭队列
+		close(b.done)
+		b.senders.Wait()
+		close(b.queue)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("events: %d queued events not processed: %w", len(b.queue), ctx.Err())
+	}
+}
@@ -0,0 +1,231 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app/config"
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/events"
+)
+
+type OrderPlaced struct {
+	ID int
+}
+
+type UnitOfWork struct {
+	ID int64
+}
+
+var unitCounter atomic.Int64
+
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{ID: unitCounter.Add(1)}
+}
+
+type AuditHandler struct {
+	Unit *UnitOfWork
+}
+
+func NewAuditHandler(unit *UnitOfWork) *AuditHandler {
+	return &AuditHandler{Unit: unit}
+}
+
+var audited []int64
+
+func (h *AuditHandler) Handle(ctx context.Context, evt OrderPlaced) error {
+	audited = append(audited, h.Unit.ID)
+	return nil
+}
+
+func startRuntime(t *testing.T, opts ...core.Option) *core.Runtime {
+	t.Helper()
+	rt := core.NewRuntime()
+	if err := rt.Apply(opts...); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := rt.Container.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if err := rt.Lifecycle.Start(context.Background(), rt.Container); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	return rt
+}
+
+func TestPublishSync(t *testing.T) {
+	var units []int64
+	var afterPanic bool
+	audited = nil
+
+	rt := startRuntime(t,
+		func(rt *core.Runtime) error {
+			if err := rt.Provide(NewUnitOfWork, di.WithScoped()); err != nil {
+				return err
+			}
+			return rt.Provide(NewAuditHandler, di.WithScoped())
+		},
+		events.New(
+			events.AddHandler[OrderPlaced](func(ctx context.Context, evt OrderPlaced, unit *UnitOfWork) {
+				units = append(units, unit.ID)
+			}),
+			events.AddService[OrderPlaced, *AuditHandler](),
+			events.AddHandler[OrderPlaced](func(evt OrderPlaced) error {
+				panic("boom")
+			}, events.WithName("crashing")),
+			events.AddHandler[OrderPlaced](func(evt OrderPlaced) {
+				afterPanic = true
+			}),
+		),
+	)
+	defer rt.Lifecycle.Stop(context.Background())
+	bus := events.From(rt)
+
+	for i := range 2 {
+		err := events.Publish(context.Background(), bus, OrderPlaced{ID: i})
+		var panicErr *core.PanicError
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("Expected PanicError from crashing handler, got %v", err)
+		}
+	}
+
+	if !afterPanic {
+		t.Error("Handlers after a panicking handler should still run")
+	}
+	if len(units) != 2 || units[0] == units[1] {
+		t.Errorf("Expected a fresh scope per dispatch, got units %v", units)
+	}
+	if len(audited) != 2 || audited[0] == units[0] {
+		t.Errorf("Expected service handler to get its own scope, got %v (func handler %v)", audited, units)
+	}
+}
+
+func TestPublishAsync(t *testing.T) {
+	var handled, started, stopping atomic.Int32
+	var reported atomic.Int32
+
+	rt := startRuntime(t,
+		func(rt *core.Runtime) error {
+			rt.ErrorHandler = func(err error) { reported.Add(1) }
+			return nil
+		},
+		events.New(
+			events.WithWorkers(2),
+			events.WithQueueSize(1),
+			events.AddHandler[OrderPlaced](func(evt OrderPlaced) error {
+				time.Sleep(5 * time.Millisecond)
+				handled.Add(1)
+				if evt.ID == 0 {
+					return errors.New("rejected")
+				}
+				return nil
+			}, events.Async()),
+			events.AddHandler[events.AppStarted](func(evt events.AppStarted) { started.Add(1) }),
+			events.AddHandler[events.AppStopping](func(evt events.AppStopping) { stopping.Add(1) }),
+		),
+	)
+	bus := events.From(rt)
+
+	for i := range 5 {
+		if err := events.Publish(context.Background(), bus, OrderPlaced{ID: i}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	// Stop 会等待队列中的事件处理完成
+	if err := rt.Lifecycle.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if handled.Load() != 5 {
+		t.Errorf("Expected 5 async dispatches, got %d", handled.Load())
+	}
+	if reported.Load() != 1 {
+		t.Errorf("Expected async failure to be reported once, got %d", reported.Load())
+	}
+	if started.Load() != 1 || stopping.Load() != 1 {
+		t.Errorf("Expected AppStarted and AppStopping once, got %d and %d", started.Load(), stopping.Load())
+	}
+
+	if err := events.Publish(context.Background(), bus, OrderPlaced{ID: 9}); !errors.Is(err, events.ErrStopped) {
+		t.Errorf("Expected ErrStopped after stop, got %v", err)
+	}
+}
+
+func TestStopWakesBlockedPublisher(t *testing.T) {
+	release := make(chan struct{})
+	rt := startRuntime(t, events.New(
+		events.WithWorkers(1),
+		events.WithQueueSize(1),
+		events.AddHandler[OrderPlaced](func(evt OrderPlaced) { <-release }, events.Async()),
+	))
+	bus := events.From(rt)
+
+	// 第一个事件占用工作协程，第二个填满队列，第三个阻塞等待空位
+	for i := range 2 {
+		if err := events.Publish(context.Background(), bus, OrderPlaced{ID: i}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	published := make(chan error, 1)
+	go func() { published <- events.Publish(context.Background(), bus, OrderPlaced{ID: 2}) }()
+	time.Sleep(10 * time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- rt.Lifecycle.Stop(context.Background()) }()
+
+	select {
+	case err := <-published:
+		if !errors.Is(err, events.ErrStopped) {
+			t.Errorf("Expected ErrStopped for the blocked publisher, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Blocked publisher was not woken by stop")
+	}
+
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
+
+func TestConfigReloadedEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("name: a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var reloaded atomic.Int32
+	rt := startRuntime(t,
+		func(rt *core.Runtime) error {
+			rt.ErrorHandler = func(err error) {}
+			return nil
+		},
+		config.Load(path),
+		events.New(events.AddHandler[events.ConfigReloaded](func(evt events.ConfigReloaded) { reloaded.Add(1) })),
+	)
+	defer rt.Lifecycle.Stop(context.Background())
+
+	if err := rt.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if reloaded.Load() != 1 {
+		t.Errorf("Expected ConfigReloaded once, got %d", reloaded.Load())
+	}
+
+	// 配置加载失败时不发布
+	if err := os.WriteFile(path, []byte("name: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.Reload(); err == nil {
+		t.Fatal("Expected Reload to fail for invalid YAML")
+	}
+	if reloaded.Load() != 1 {
+		t.Errorf("Expected no ConfigReloaded after a failed reload, got %d", reloaded.Load())
+	}
+}
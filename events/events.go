@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"errors"
+)
+
+// Handler 事件处理器接口
+// 通过 AddService 注册的处理器在每次分发时从新的 DI 作用域中解析，
+// 因此可以依赖 Scoped 服务（例如数据库事务、工作单元）。
+type Handler[T any] interface {
+	Handle(ctx context.Context, evt T) error
+}
+
+// ErrorPolicy 同步处理器失败时的处理策略
+type ErrorPolicy int
+
+const (
+	// ContinueOnError 继续执行其余处理器，Publish 返回所有错误的组合（默认）
+	ContinueOnError ErrorPolicy = iota
+	// StopOnError 某个处理器失败后不再执行后续的同步处理器，Publish 返回该错误
+	StopOnError
+	// ReportErrors 错误只通过 Runtime.ErrorHandler 报告，Publish 总是返回 nil
+	ReportErrors
+)
+
+// ErrStopped 事件总线已停止，不再接受异步事件
+var ErrStopped = errors.New("events: bus is stopped")
+
+// AppStarted 应用启动完成（所有启动钩子执行成功）后发布
+type AppStarted struct{}
+
+// AppStopping 应用开始停止时发布，此时其他服务仍在运行
+type AppStopping struct{}
+
+// ConfigReloaded 配置重新加载成功后发布
+type ConfigReloaded struct{}
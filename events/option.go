@@ -0,0 +1,167 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocrud/app/config"
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+// Builder 事件总线配置
+type Builder struct {
+	workers       int
+	queueSize     int
+	policy        ErrorPolicy
+	subscriptions []func(*Bus) error
+}
+
+// BuilderOption 用于配置事件总线
+type BuilderOption func(*Builder)
+
+// WithWorkers 设置异步处理器的工作协程数量（默认 4）
+func WithWorkers(n int) BuilderOption {
+	return func(b *Builder) {
+		b.workers = n
+	}
+}
+
+// WithQueueSize 设置异步事件队列的容量（默认 1024）
+func WithQueueSize(n int) BuilderOption {
+	return func(b *Builder) {
+		b.queueSize = n
+	}
+}
+
+// WithErrorPolicy 设置同步处理器失败时的处理策略（默认 ContinueOnError）
+func WithErrorPolicy(policy ErrorPolicy) BuilderOption {
+	return func(b *Builder) {
+		b.policy = policy
+	}
+}
+
+// AddHandler 注册函数处理器，见 Subscribe
+func AddHandler[T any](handler any, opts ...HandlerOption) BuilderOption {
+	return func(b *Builder) {
+		b.subscriptions = append(b.subscriptions, func(bus *Bus) error {
+			return Subscribe[T](bus, handler, opts...)
+		})
+	}
+}
+
+// AddService 注册服务处理器，见 SubscribeService
+func AddService[T any, H Handler[T]](opts ...HandlerOption) BuilderOption {
+	return func(b *Builder) {
+		b.subscriptions = append(b.subscriptions, func(bus *Bus) error {
+			SubscribeService[T, H](bus, opts...)
+			return nil
+		})
+	}
+}
+
+// New 启用事件总线
+// 注册 *Bus 到 DI 容器，并在应用启动完成、开始停止时发布 AppStarted 与 AppStopping，
+// 通过 config.Load 加载的配置重新加载成功后发布 ConfigReloaded。
+// 总线在基础设施阶段启动、最后停止，停止时会等待队列中的异步事件处理完成。
+func New(opts ...BuilderOption) core.Option {
+	return core.WithModule(core.NewModule("events", func(rt *core.Runtime) error {
+		builder := &Builder{
+			workers:   4,
+			queueSize: 1024,
+			policy:    ContinueOnError,
+		}
+		for _, opt := range opts {
+			opt(builder)
+		}
+		if builder.workers <= 0 {
+			return fmt.Errorf("events: workers must be positive, got %d", builder.workers)
+		}
+
+		report := func(err error) {
+			if rt.ErrorHandler != nil {
+				rt.ErrorHandler(err)
+			}
+		}
+		bus := newBus(rt.Container, builder.workers, builder.queueSize, builder.policy, report)
+		for _, subscribe := range builder.subscriptions {
+			if err := subscribe(bus); err != nil {
+				return err
+			}
+		}
+
+		di.ProvideService[*Bus](rt.Container, di.WithValue(bus))
+		rt.Features.Set(bus)
+
+		// 应用生命周期事件在独立的 Goroutine 中发布，停止总线前等待 AppStopping 处理完成
+		lifecycleDone := make(chan struct{})
+		rt.Lifecycle.Append(core.Hook{
+			Name:  "events",
+			Stage: core.StageInfrastructure,
+			OnStart: func(ctx context.Context) error {
+				lifetime, err := di.Get[core.ApplicationLifetime](rt.Container)
+				if err != nil {
+					return err
+				}
+				bus.start()
+
+				go func() {
+					defer close(lifecycleDone)
+
+					select {
+					case <-lifetime.Started():
+					case <-lifetime.Stopping():
+					}
+					// 启动失败回滚时只发布 AppStopping
+					select {
+					case <-lifetime.Started():
+						if err := Publish(context.Background(), bus, AppStarted{}); err != nil {
+							report(err)
+						}
+					default:
+					}
+
+					<-lifetime.Stopping()
+					if err := Publish(context.Background(), bus, AppStopping{}); err != nil {
+						report(err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				select {
+				case <-lifecycleDone:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				return bus.stop(ctx)
+			},
+		})
+
+		// 配置在基础设施阶段重新加载，这里在服务阶段检查是否加载成功并通知订阅者
+		var generation uint64
+		rt.Lifecycle.Append(core.Hook{
+			Name:  "events.config",
+			Stage: core.StageServices,
+			OnStart: func(ctx context.Context) error {
+				generation = config.Generation(rt)
+				return nil
+			},
+			OnReload: func(ctx context.Context) error {
+				current := config.Generation(rt)
+				if current == generation {
+					return nil
+				}
+				generation = current
+				return Publish(ctx, bus, ConfigReloaded{})
+			},
+		})
+
+		return nil
+	}))
+}
+
+// From 获取 Runtime 中的事件总线，未启用事件模块时返回 nil
+func From(rt *core.Runtime) *Bus {
+	return core.GetFeature[*Bus](rt)
+}
@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Status 健康状态
+// 数值越大表示状态越差，汇总时取所有检查中最差的状态。
+type Status int
+
+const (
+	// StatusHealthy 健康
+	StatusHealthy Status = iota
+	// StatusDegraded 降级：非关键依赖不可用，服务仍可对外提供能力
+	StatusDegraded
+	// StatusUnhealthy 不健康
+	StatusUnhealthy
+)
+
+// String 返回状态名称
+func (s Status) String() string {
+	switch s {
+	case StatusHealthy:
+		return "Healthy"
+	case StatusDegraded:
+		return "Degraded"
+	case StatusUnhealthy:
+		return "Unhealthy"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// MarshalJSON 以状态名称输出
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+const (
+	// TagLive 存活检查标签：进程是否仍在正常运行，失败时通常应重启进程
+	TagLive = "live"
+	// TagReady 就绪检查标签：是否可以接收流量，失败时应暂时摘除流量
+	TagReady = "ready"
+)
+
+// Check 健康检查
+type Check interface {
+	// Check 执行检查，返回 nil 表示健康
+	// ctx 带有检查的超时时间
+	Check(ctx context.Context) error
+}
+
+// CheckGroup DI 值组名称
+// 以 di.InGroup(health.CheckGroup) 注册到容器的 Check 会在应用启动时加入 RegistryFrom 返回的注册表。
+const CheckGroup = "health-checks"
+
+// Named 容器中的检查可以实现 Named 指定检查名称，未实现时使用类型名称
+type Named interface {
+	Name() string
+}
+
+// Configured 容器中的检查可以实现 Configured 提供注册选项（标签、超时、缓存等）
+type Configured interface {
+	CheckOptions() []Option
+}
+
+// CheckFunc 将普通函数适配为 Check
+type CheckFunc func(ctx context.Context) error
+
+// Check 实现 Check 接口
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result 单个检查的结果
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Tags     []string      `json:"tags,omitempty"`
+	Err      error         `json:"-"`
+	// Cached 表示结果来自缓存
+	Cached bool `json:"cached,omitempty"`
+}
+
+// MarshalJSON 额外输出错误信息，耗时以字符串表示
+func (r Result) MarshalJSON() ([]byte, error) {
+	type result Result
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		result
+		Duration string `json:"duration"`
+		Error    string `json:"error,omitempty"`
+	}{result(r), r.Duration.String(), errMsg})
+}
+
+// Report 汇总的健康报告
+type Report struct {
+	Status   Status        `json:"status"`
+	Duration time.Duration `json:"-"`
+	Checks   []Result      `json:"checks"`
+}
+
+// Healthy 报告状态不为 Unhealthy 时返回 true（降级视为可用）
+func (r Report) Healthy() bool {
+	return r.Status != StatusUnhealthy
+}
+
+// MarshalJSON 耗时以字符串表示
+func (r Report) MarshalJSON() ([]byte, error) {
+	type report Report
+	return json.Marshal(struct {
+		report
+		Duration string `json:"duration"`
+	}{report(r), r.Duration.String()})
+}
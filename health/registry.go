@@ -0,0 +1,265 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+// Option 配置单个健康检查
+type Option func(*registration)
+
+// WithTags 设置检查的标签（默认 TagReady）
+func WithTags(tags ...string) Option {
+	return func(r *registration) {
+		r.tags = tags
+	}
+}
+
+// WithTimeout 设置检查的超时时间（默认 5 秒）
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *registration) {
+		r.timeout = timeout
+	}
+}
+
+// WithCache 缓存检查结果，在 ttl 内重复检查直接返回上一次的结果
+// 适用于代价较高或被频繁探测的检查。
+func WithCache(ttl time.Duration) Option {
+	return func(r *registration) {
+		r.cacheTTL = ttl
+	}
+}
+
+// WithFailureStatus 设置检查失败时报告的状态（默认 StatusUnhealthy）
+// 非关键依赖可以设置为 StatusDegraded。
+func WithFailureStatus(status Status) Option {
+	return func(r *registration) {
+		r.failureStatus = status
+	}
+}
+
+// registration 已注册的检查
+type registration struct {
+	name          string
+	check         Check
+	tags          []string
+	timeout       time.Duration
+	cacheTTL      time.Duration
+	failureStatus Status
+
+	mu       sync.Mutex
+	cached   Result
+	cachedAt time.Time
+}
+
+// Registry 健康检查注册表
+// 通过 RegistryFrom 获取的注册表同时注册在 Runtime Features 和 DI 容器中。
+type Registry struct {
+	mu     sync.RWMutex
+	checks []*registration
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register 注册健康检查，名称必须唯一
+func (r *Registry) Register(name string, check Check, opts ...Option) error {
+	reg := &registration{
+		name:          name,
+		check:         check,
+		tags:          []string{TagReady},
+		timeout:       5 * time.Second,
+		failureStatus: StatusUnhealthy,
+	}
+	for _, opt := range opts {
+		opt(reg)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.checks {
+		if existing.name == name {
+			return fmt.Errorf("health: check '%s' already registered", name)
+		}
+	}
+	r.checks = append(r.checks, reg)
+	return nil
+}
+
+// RegisterFromContainer 注册容器中 CheckGroup 组内的所有检查
+// 检查名称与选项来自可选的 Named 与 Configured 接口。
+func (r *Registry) RegisterFromContainer(c di.Container) error {
+	checks, err := di.GetGroup[Check](c, CheckGroup)
+	if err != nil {
+		return fmt.Errorf("health: failed to resolve checks: %w", err)
+	}
+	for _, check := range checks {
+		name := fmt.Sprintf("%T", check)
+		if named, ok := check.(Named); ok {
+			name = named.Name()
+		}
+		var opts []Option
+		if configured, ok := check.(Configured); ok {
+			opts = configured.CheckOptions()
+		}
+		if err := r.Register(name, check, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterFunc 注册函数形式的健康检查
+func (r *Registry) RegisterFunc(name string, fn func(ctx context.Context) error, opts ...Option) error {
+	return r.Register(name, CheckFunc(fn), opts...)
+}
+
+// Check 并发执行带有任一指定标签的检查并汇总结果
+// 不传标签时执行所有检查。没有匹配的检查时报告为健康。
+func (r *Registry) Check(ctx context.Context, tags ...string) Report {
+	r.mu.RLock()
+	var selected []*registration
+	for _, reg := range r.checks {
+		if len(tags) == 0 || slices.ContainsFunc(tags, func(tag string) bool { return slices.Contains(reg.tags, tag) }) {
+			selected = append(selected, reg)
+		}
+	}
+	r.mu.RUnlock()
+
+	begin := time.Now()
+	report := Report{Status: StatusHealthy, Checks: make([]Result, len(selected))}
+
+	var wg sync.WaitGroup
+	for i, reg := range selected {
+		wg.Go(func() {
+			report.Checks[i] = reg.run(ctx)
+		})
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		report.Status = max(report.Status, result.Status)
+	}
+	report.Duration = time.Since(begin)
+	return report
+}
+
+// Live 执行存活检查
+func (r *Registry) Live(ctx context.Context) Report {
+	return r.Check(ctx, TagLive)
+}
+
+// Ready 执行就绪检查
+func (r *Registry) Ready(ctx context.Context) Report {
+	return r.Check(ctx, TagReady)
+}
+
+// run 执行单个检查，命中缓存时直接返回缓存结果
+func (reg *registration) run(ctx context.Context) Result {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.cacheTTL > 0 && !reg.cachedAt.IsZero() && time.Since(reg.cachedAt) < reg.cacheTTL {
+		result := reg.cached
+		result.Cached = true
+		return result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, reg.timeout)
+	defer cancel()
+
+	begin := time.Now()
+	err := reg.execute(checkCtx)
+	result := Result{
+		Name:     reg.name,
+		Status:   StatusHealthy,
+		Duration: time.Since(begin),
+		Tags:     reg.tags,
+		Err:      err,
+	}
+	if err != nil {
+		result.Status = reg.failureStatus
+	}
+
+	// 调用方取消导致的失败不代表检查目标的状态，不缓存
+	if err == nil || ctx.Err() == nil {
+		reg.cached = result
+		reg.cachedAt = time.Now()
+	}
+	return result
+}
+
+// execute 执行检查，检查不响应 ctx 取消时按超时处理，panic 视为失败
+func (reg *registration) execute(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- reg.check.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("check timed out after %v", reg.timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// RegistryFrom 获取 Runtime 中的健康检查注册表，不存在时创建
+// 首次创建时会将注册表注册到 DI 容器，并添加一个反映应用生命周期的就绪检查：
+// 应用启动完成前或开始停止后，就绪检查报告为不健康。
+// 应用启动时（StageInfrastructure 阶段）注册容器中 CheckGroup 组内的检查。
+func RegistryFrom(rt *core.Runtime) *Registry {
+	if registry := core.GetFeature[*Registry](rt); registry != nil {
+		return registry
+	}
+
+	registry := NewRegistry()
+	rt.Features.Set(registry)
+	di.ProvideService[*Registry](rt.Container, di.WithValue(registry))
+
+	_ = registry.RegisterFunc("application", func(ctx context.Context) error {
+		lifetime, err := di.Get[core.ApplicationLifetime](rt.Container)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-lifetime.Stopping():
+			return errors.New("application is stopping")
+		default:
+		}
+		select {
+		case <-lifetime.Started():
+			return nil
+		default:
+			return errors.New("application is starting")
+		}
+	}, WithTags(TagReady))
+
+	rt.Lifecycle.Append(core.Hook{
+		Name:  "health",
+		Stage: core.StageInfrastructure,
+		OnStart: func(ctx context.Context) error {
+			return registry.RegisterFromContainer(rt.Container)
+		},
+	})
+
+	return registry
+}
@@ -0,0 +1,138 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
+)
+
+func TestRegistryAggregation(t *testing.T) {
+	r := health.NewRegistry()
+	_ = r.RegisterFunc("ok", func(ctx context.Context) error { return nil })
+	_ = r.RegisterFunc("cache", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}, health.WithFailureStatus(health.StatusDegraded))
+
+	report := r.Ready(context.Background())
+	if report.Status != health.StatusDegraded {
+		t.Errorf("Expected Degraded, got %v", report.Status)
+	}
+	if !report.Healthy() {
+		t.Error("Degraded report should still be considered healthy")
+	}
+
+	_ = r.RegisterFunc("db", func(ctx context.Context) error {
+		return errors.New("timeout")
+	})
+	report = r.Ready(context.Background())
+	if report.Status != health.StatusUnhealthy {
+		t.Errorf("Expected Unhealthy, got %v", report.Status)
+	}
+	if len(report.Checks) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(report.Checks))
+	}
+
+	if err := r.RegisterFunc("db", func(ctx context.Context) error { return nil }); err == nil {
+		t.Error("Expected error for duplicate check name")
+	}
+}
+
+func TestRegistryTags(t *testing.T) {
+	r := health.NewRegistry()
+	_ = r.RegisterFunc("process", func(ctx context.Context) error { return nil }, health.WithTags(health.TagLive))
+	_ = r.RegisterFunc("db", func(ctx context.Context) error { return errors.New("down") })
+
+	if report := r.Live(context.Background()); report.Status != health.StatusHealthy || len(report.Checks) != 1 {
+		t.Errorf("Liveness should only run live checks, got %+v", report)
+	}
+	if report := r.Ready(context.Background()); report.Status != health.StatusUnhealthy {
+		t.Errorf("Expected readiness to be Unhealthy, got %v", report.Status)
+	}
+}
+
+func TestRegistryTimeoutAndCache(t *testing.T) {
+	r := health.NewRegistry()
+
+	_ = r.RegisterFunc("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, health.WithTimeout(20*time.Millisecond))
+
+	var calls atomic.Int32
+	_ = r.RegisterFunc("cached", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}, health.WithCache(time.Minute))
+
+	report := r.Check(context.Background())
+	if report.Status != health.StatusUnhealthy {
+		t.Errorf("Expected slow check to time out, got %v", report.Status)
+	}
+	if report.Duration > 500*time.Millisecond {
+		t.Errorf("Timed out check should not block the report, took %v", report.Duration)
+	}
+
+	// 调用方取消导致的失败不会被缓存
+	_ = r.RegisterFunc("remote", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, health.WithCache(time.Minute), health.WithTags("remote"))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := r.Check(canceled, "remote"); report.Status != health.StatusUnhealthy || report.Checks[0].Cached {
+		t.Errorf("Expected canceled check to fail, got %+v", report)
+	}
+	if report := r.Check(canceled, "remote"); report.Checks[0].Cached {
+		t.Error("Expected failure caused by caller cancellation not to be cached")
+	}
+
+	report = r.Check(context.Background(), health.TagReady)
+	if calls.Load() != 1 {
+		t.Errorf("Expected cached check to run once, got %d", calls.Load())
+	}
+	for _, result := range report.Checks {
+		if result.Name == "cached" && !result.Cached {
+			t.Error("Expected second result to come from cache")
+		}
+	}
+}
+
+type queueCheck struct{}
+
+func (queueCheck) Check(ctx context.Context) error { return errors.New("queue unavailable") }
+
+func (queueCheck) Name() string { return "queue" }
+
+func (queueCheck) CheckOptions() []health.Option {
+	return []health.Option{health.WithFailureStatus(health.StatusDegraded)}
+}
+
+type diskCheck struct{}
+
+func (*diskCheck) Check(ctx context.Context) error { return nil }
+
+func TestRegistryFromContainer(t *testing.T) {
+	c := di.NewContainer()
+	_, _ = di.Provide(c, func() health.Check { return queueCheck{} }, di.InGroup(health.CheckGroup))
+	_, _ = di.Provide(c, func() health.Check { return &diskCheck{} }, di.InGroup(health.CheckGroup))
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	r := health.NewRegistry()
+	if err := r.RegisterFromContainer(c); err != nil {
+		t.Fatalf("RegisterFromContainer failed: %v", err)
+	}
+	report := r.Ready(context.Background())
+	if report.Status != health.StatusDegraded || len(report.Checks) != 2 {
+		t.Fatalf("Expected degraded report with 2 checks, got %+v", report)
+	}
+	if report.Checks[0].Name != "queue" || report.Checks[1].Name != "*health_test.diskCheck" {
+		t.Errorf("Unexpected check names %s, %s", report.Checks[0].Name, report.Checks[1].Name)
+	}
+}
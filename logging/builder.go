@@ -9,6 +9,7 @@ import (
 type LoggingBuilder struct {
 	providers    []LoggerProvider
 	minimumLevel LogLevel
+	levelSet     bool
 	mu           sync.RWMutex
 }
 
@@ -25,6 +26,7 @@ func (b *LoggingBuilder) SetMinimumLevel(level LogLevel) *LoggingBuilder {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	b.minimumLevel = level
+	b.levelSet = true
 	return b
 }
 
@@ -72,7 +74,7 @@ func (b *LoggingBuilder) Build() LoggerFactory {
 	// 创建日志工厂
 	factory := &loggerFactory{
 		providers:    make([]LoggerProvider, 0),
-		minimumLevel: b.minimumLevel,
+		minimumLevel: newLevelVar(b.minimumLevel),
 	}
 
 	for _, provider := range b.providers {
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -40,6 +42,48 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLevel 解析日志级别名称（忽略大小写），例如 "debug"、"Info"、"WARN"
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "TRACE":
+		return LogLevelTrace, nil
+	case "DEBUG":
+		return LogLevelDebug, nil
+	case "INFO", "INFORMATION":
+		return LogLevelInfo, nil
+	case "WARN", "WARNING":
+		return LogLevelWarn, nil
+	case "ERROR":
+		return LogLevelError, nil
+	case "FATAL":
+		return LogLevelFatal, nil
+	default:
+		return LogLevelInfo, fmt.Errorf("logging: unknown level %q", name)
+	}
+}
+
+// levelVar 可在运行时修改的日志级别
+// 由提供者（或工厂）及其创建的所有日志记录器共享，修改后立即对已创建的记录器生效。
+type levelVar struct {
+	v atomic.Int32
+}
+
+func newLevelVar(level LogLevel) *levelVar {
+	l := &levelVar{}
+	l.Set(level)
+	return l
+}
+
+// Level 返回当前日志级别
+func (l *levelVar) Level() LogLevel {
+	return LogLevel(l.v.Load())
+}
+
+// Set 修改日志级别
+func (l *levelVar) Set(level LogLevel) {
+	l.v.Store(int32(level))
+}
+
 // Field 日志字段
 type Field struct {
 	Key   string
@@ -76,7 +120,7 @@ type LoggerProvider interface {
 // loggerFactory 日志工厂实现
 type loggerFactory struct {
 	providers    []LoggerProvider
-	minimumLevel LogLevel
+	minimumLevel *levelVar
 	mu           sync.RWMutex
 }
 
@@ -99,14 +143,14 @@ func (f *loggerFactory) CreateLogger(category string) Logger {
 func (f *loggerFactory) AddProvider(provider LoggerProvider) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	provider.SetMinimumLevel(f.minimumLevel)
+	provider.SetMinimumLevel(f.minimumLevel.Level())
 	f.providers = append(f.providers, provider)
 }
 
 func (f *loggerFactory) SetMinimumLevel(level LogLevel) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	f.minimumLevel = level
+	f.minimumLevel.Set(level)
 	for _, provider := range f.providers {
 		provider.SetMinimumLevel(level)
 	}
@@ -115,7 +159,7 @@ func (f *loggerFactory) SetMinimumLevel(level LogLevel) {
 // compositeLogger 组合日志记录器（将日志发送到多个提供者）
 type compositeLogger struct {
 	loggers      []Logger
-	minimumLevel LogLevel
+	minimumLevel *levelVar
 	category     string
 	fields       []Field
 }
@@ -124,7 +168,7 @@ type compositeLogger struct {
 func NewCompositeLogger(loggers []Logger, minimumLevel LogLevel, category string) Logger {
 	return &compositeLogger{
 		loggers:      loggers,
-		minimumLevel: minimumLevel,
+		minimumLevel: newLevelVar(minimumLevel),
 		category:     category,
 		fields:       make([]Field, 0),
 	}
@@ -156,7 +200,7 @@ func (l *compositeLogger) Fatal(msg string, fields ...Field) {
 }
 
 func (l *compositeLogger) Log(level LogLevel, msg string, fields ...Field) {
-	if level < l.minimumLevel {
+	if level < l.minimumLevel.Level() {
 		return
 	}
 
@@ -197,7 +241,7 @@ type ConsoleLoggerOptions struct {
 // ConsoleLoggerProvider 控制台日志提供者
 type ConsoleLoggerProvider struct {
 	options      ConsoleLoggerOptions
-	minimumLevel LogLevel
+	minimumLevel *levelVar
 	mu           sync.RWMutex
 	asyncWriter  *AsyncWriter
 }
@@ -218,7 +262,7 @@ func NewConsoleLoggerProvider(options ConsoleLoggerOptions) *ConsoleLoggerProvid
 
 	return &ConsoleLoggerProvider{
 		options:      options,
-		minimumLevel: LogLevelInfo,
+		minimumLevel: newLevelVar(LogLevelInfo),
 		asyncWriter:  asyncWriter,
 	}
 }
@@ -234,14 +278,14 @@ func (p *ConsoleLoggerProvider) CreateLogger(category string) Logger {
 func (p *ConsoleLoggerProvider) SetMinimumLevel(level LogLevel) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.minimumLevel = level
+	p.minimumLevel.Set(level)
 }
 
 // consoleLogger 控制台日志实现
 type consoleLogger struct {
 	category     string
 	writer       *AsyncWriter
-	minimumLevel LogLevel
+	minimumLevel *levelVar
 	fields       []Field
 }
 
@@ -271,7 +315,7 @@ func (l *consoleLogger) Fatal(msg string, fields ...Field) {
 }
 
 func (l *consoleLogger) Log(level LogLevel, msg string, fields ...Field) {
-	if level < l.minimumLevel {
+	if level < l.minimumLevel.Level() {
 		return
 	}
 
@@ -345,7 +389,7 @@ type FileLoggerOptions struct {
 // FileLoggerProvider 文件日志提供者
 type FileLoggerProvider struct {
 	options      FileLoggerOptions
-	minimumLevel LogLevel
+	minimumLevel *levelVar
 	file         *os.File
 	mu           sync.RWMutex
 	asyncWriter  *AsyncWriter
@@ -354,7 +398,7 @@ type FileLoggerProvider struct {
 func NewFileLoggerProvider(options FileLoggerOptions) *FileLoggerProvider {
 	return &FileLoggerProvider{
 		options:      options,
-		minimumLevel: LogLevelInfo,
+		minimumLevel: newLevelVar(LogLevelInfo),
 	}
 }
 
@@ -394,14 +438,14 @@ func (p *FileLoggerProvider) CreateLogger(category string) Logger {
 func (p *FileLoggerProvider) SetMinimumLevel(level LogLevel) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.minimumLevel = level
+	p.minimumLevel.Set(level)
 }
 
 // fileLogger 文件日志实现
 type fileLogger struct {
 	category     string
 	writer       *AsyncWriter
-	minimumLevel LogLevel
+	minimumLevel *levelVar
 	fields       []Field
 }
 
@@ -431,7 +475,7 @@ func (l *fileLogger) Fatal(msg string, fields ...Field) {
 }
 
 func (l *fileLogger) Log(level LogLevel, msg string, fields ...Field) {
-	if level < l.minimumLevel {
+	if level < l.minimumLevel.Level() {
 		return
 	}
 
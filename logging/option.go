@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/gocrud/app/config"
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+)
+
+// BuilderOption 用于配置 Logging Builder
+type BuilderOption func(*LoggingBuilder)
+
+// WithMinimumLevel 设置最小日志级别
+// 未设置时读取配置项 logging.level，配置也未设置时开发环境为 Debug，其他环境为 Info。
+func WithMinimumLevel(level LogLevel) BuilderOption {
+	return func(b *LoggingBuilder) {
+		b.SetMinimumLevel(level)
+	}
+}
+
+// WithConsole 添加控制台日志
+// 未添加任何提供者时，默认添加控制台日志，仅在开发环境输出颜色。
+func WithConsole(options ConsoleLoggerOptions) BuilderOption {
+	return func(b *LoggingBuilder) {
+		b.AddConsole(options)
+	}
+}
+
+// WithFile 添加文件日志
+func WithFile(path string) BuilderOption {
+	return func(b *LoggingBuilder) {
+		b.AddFile(path)
+	}
+}
+
+// New 启用日志能力
+// 注册 LoggerFactory 与默认 Logger 到 DI 容器，并接管 Runtime.ErrorHandler、Runtime.ShutdownReporter 与 Runtime.DiagnosticsReporter。
+// 未通过 WithMinimumLevel 指定级别时，日志级别跟随配置项 logging.level，重新加载配置后立即生效。
+func New(opts ...BuilderOption) core.Option {
+	return core.WithModule(core.NewModule("logging", func(rt *core.Runtime) error {
+		env := core.EnvironmentFrom(rt)
+		development := env != nil && env.IsDevelopment()
+
+		builder := NewLoggingBuilder()
+		for _, opt := range opts {
+			opt(builder)
+		}
+		explicit := builder.levelSet
+		defaultLevel := builder.minimumLevel
+		if !explicit && development {
+			defaultLevel = LogLevelDebug
+			builder.SetMinimumLevel(defaultLevel)
+		}
+
+		if len(builder.providers) == 0 {
+			builder.AddConsole(ConsoleLoggerOptions{
+				IncludeTimestamp: true,
+				TimestampFormat:  "2006-01-02 15:04:05",
+				ColorOutput:      development,
+				Output:           os.Stdout,
+			})
+		}
+
+		factory := builder.Build()
+		category := "app"
+		if env != nil && env.AppName != "" {
+			category = env.AppName
+		}
+		logger := factory.CreateLogger(category)
+
+		// 注册到 DI 容器与 Feature
+		di.ProvideService[LoggerFactory](rt.Container, di.WithValue(factory))
+		di.ProvideService[Logger](rt.Container, di.WithValue(logger))
+		rt.Features.Set(factory)
+
+		// 运行时错误统一输出到日志
+		rt.ErrorHandler = func(err error) {
+			logger.Error(err.Error())
+		}
+
+		// 关闭报告输出到日志，存在失败的钩子时使用 Error 级别
+		rt.ShutdownReporter = func(report core.ShutdownReport) {
+			if report.Failed() {
+				logger.Error(report.String())
+				return
+			}
+			logger.Info(report.String())
+		}
+
+		// 诊断快照以文本格式输出到日志
+		rt.DiagnosticsReporter = func(d core.Diagnostics) {
+			var buf strings.Builder
+			_, _ = d.WriteTo(&buf)
+			logger.Info(buf.String())
+		}
+
+		// 日志级别跟随配置：启动时与每次重新加载时读取 logging.level
+		if !explicit {
+			applyLevel := func(ctx context.Context) error {
+				level, err := configuredLevel(rt, defaultLevel)
+				if err != nil {
+					return err
+				}
+				factory.SetMinimumLevel(level)
+				return nil
+			}
+			if err := applyLevel(context.Background()); err != nil {
+				return err
+			}
+			rt.Lifecycle.Append(core.Hook{
+				Name:     "logging",
+				Stage:    core.StageServices,
+				OnStart:  applyLevel,
+				OnReload: applyLevel,
+			})
+		}
+
+		return nil
+	}))
+}
+
+// configuredLevel 读取配置项 logging.level，未加载配置或未设置时返回 fallback
+func configuredLevel(rt *core.Runtime, fallback LogLevel) (LogLevel, error) {
+	cfg := config.From(rt)
+	if cfg == nil {
+		return fallback, nil
+	}
+	name := cfg.Get("logging.level")
+	if name == "" {
+		return fallback, nil
+	}
+	return ParseLevel(name)
+}
+
+// FactoryFrom 获取 Runtime 中的日志工厂，未启用日志模块时返回 nil
+// 供需要在构建阶段记录日志的模块使用（此时 DI 容器尚未构建）。
+func FactoryFrom(rt *core.Runtime) LoggerFactory {
+	if factory := core.GetFeature[*loggerFactory](rt); factory != nil {
+		return factory
+	}
+	return nil
+}
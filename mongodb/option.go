@@ -6,6 +6,7 @@ import (
 
 	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
 	"github.com/gocrud/mgo"
 )
 
@@ -29,7 +30,7 @@ func WithClient(name string, uri string, opts ...func(*MongoOptions)) BuilderOpt
 
 // New 启用 MongoDB 能力
 func New(opts ...BuilderOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("mongodb", func(rt *core.Runtime) error {
 		builder := NewBuilder()
 		for _, opt := range opts {
 			opt(builder)
@@ -50,6 +51,7 @@ func New(opts ...BuilderOption) core.Option {
 		}
 
 		// 注册 Client 实例
+		registry := health.RegistryFrom(rt)
 		var defaultRegErr error
 		factory.Each(func(name string, client *mgo.Client) {
 			if err := rt.Provide(client, di.WithName(name), di.WithValue(client)); err != nil {
@@ -61,6 +63,13 @@ func New(opts ...BuilderOption) core.Option {
 					defaultRegErr = err
 				}
 			}
+
+			// 注册健康检查
+			if err := registry.RegisterFunc("mongodb:"+name, func(ctx context.Context) error {
+				return client.Ping(ctx)
+			}); err != nil {
+				defaultRegErr = err
+			}
 		})
 
 		if defaultRegErr != nil {
@@ -71,8 +80,8 @@ func New(opts ...BuilderOption) core.Option {
 		rt.Lifecycle.OnStop(func(ctx context.Context) error {
 			fmt.Println("Closing mongo clients")
 			return factory.Close()
-		})
+		}, core.WithHookName("mongodb"), core.InStage(core.StageInfrastructure))
 
 		return nil
-	}
+	}))
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -29,7 +30,7 @@ func WithClient(name string, opts ...func(*RedisClientOptions)) BuilderOption {
 
 // New 启用 Redis 能力
 func New(opts ...BuilderOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("redis", func(rt *core.Runtime) error {
 		builder := NewBuilder()
 		for _, opt := range opts {
 			opt(builder)
@@ -50,6 +51,7 @@ func New(opts ...BuilderOption) core.Option {
 		}
 
 		// 注册各个客户端
+		registry := health.RegistryFrom(rt)
 		var defaultRegErr error
 		factory.Each(func(name string, client *redis.Client) {
 			if err := rt.Provide(client, di.WithName(name), di.WithValue(client)); err != nil {
@@ -61,18 +63,27 @@ func New(opts ...BuilderOption) core.Option {
 					defaultRegErr = err
 				}
 			}
+
+			// 注册健康检查
+			if err := registry.RegisterFunc("redis:"+name, func(ctx context.Context) error {
+				return client.Ping(ctx).Err()
+			}); err != nil {
+				defaultRegErr = err
+			}
 		})
 
 		if defaultRegErr != nil {
 			return fmt.Errorf("redis: failed to register instance: %w", defaultRegErr)
 		}
 
+		rt.AddDiagnostics("redis", func() any { return factory.Stats() })
+
 		// 注册清理钩子
 		rt.Lifecycle.OnStop(func(ctx context.Context) error {
 			fmt.Println("Closing redis clients")
 			return factory.Close()
-		})
+		}, core.WithHookName("redis"), core.InStage(core.StageInfrastructure))
 
 		return nil
-	}
+	}))
 }
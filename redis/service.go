@@ -109,6 +109,18 @@ func (f *RedisClientFactory) Each(fn func(name string, client *redis.Client)) {
 	}
 }
 
+// Stats 返回每个客户端的连接池状态
+func (f *RedisClientFactory) Stats() map[string]*redis.PoolStats {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	stats := make(map[string]*redis.PoolStats, len(f.clients))
+	for name, client := range f.clients {
+		stats[name] = client.PoolStats()
+	}
+	return stats
+}
+
 // Close 关闭所有 Redis 客户端
 func (f *RedisClientFactory) Close() error {
 	f.mu.Lock()
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package app
+
+import "os"
+
+// 当前平台不支持 SIGUSR1/SIGUSR2，优雅升级不可用，诊断快照只能通过 rt.DumpDiagnostics 触发
+var (
+	defaultUpgradeSignals     []os.Signal
+	defaultDiagnosticsSignals []os.Signal
+)
@@ -0,0 +1,15 @@
+//go:build unix
+
+package app
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	// defaultUpgradeSignals 默认触发优雅升级的信号
+	defaultUpgradeSignals = []os.Signal{syscall.SIGUSR2}
+	// defaultDiagnosticsSignals 默认触发诊断快照的信号
+	defaultDiagnosticsSignals = []os.Signal{syscall.SIGUSR1}
+)
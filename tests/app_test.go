@@ -0,0 +1,580 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/app"
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/cron"
+	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/logging"
+)
+
+func TestAppStartStop(t *testing.T) {
+	stopped := make(chan struct{})
+
+	a, err := app.New(
+		app.WithSignals(),
+		core.WithWorker(func(ctx context.Context) error {
+			<-ctx.Done()
+			close(stopped)
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := a.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Worker should be stopped")
+	}
+
+	if err := a.Wait(); err != nil {
+		t.Errorf("Expected clean exit, got %v", err)
+	}
+	if a.Err() != nil {
+		t.Errorf("Expected no shutdown cause, got %v", a.Err())
+	}
+}
+
+func TestAppCrashedWorker(t *testing.T) {
+	crash := errors.New("connection lost")
+
+	a, err := app.New(
+		app.WithSignals(),
+		app.WithShutdownTimeout(time.Second),
+		core.WithWorker(func(ctx context.Context) error {
+			return crash
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	a.Runtime().ErrorHandler = func(error) {}
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Wait() }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, crash) {
+			t.Errorf("Expected Wait to return worker error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("App should stop after worker crash")
+	}
+
+	if !errors.Is(a.Err(), crash) {
+		t.Errorf("Expected Err to return worker error, got %v", a.Err())
+	}
+}
+
+func TestWorkerRestartPolicy(t *testing.T) {
+	flaky := errors.New("flaky")
+
+	var runs atomic.Int32
+	var restarts atomic.Int32
+
+	a, err := app.New(
+		app.WithSignals(),
+		core.WithWorker(func(ctx context.Context) error {
+			runs.Add(1)
+			return flaky
+		}, core.WithRestartPolicy(core.RestartPolicy{
+			Mode:           core.RestartOnFailure,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+			MaxRestarts:    3,
+			Window:         time.Minute,
+		})),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	a.Runtime().ErrorHandler = func(err error) {
+		if strings.Contains(err.Error(), "restarting") {
+			restarts.Add(1)
+		}
+	}
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Wait() }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, flaky) {
+			t.Errorf("Expected Wait to return worker error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("App should stop after restart budget is exhausted")
+	}
+
+	// 初次运行 + 3 次重启
+	if runs.Load() != 4 {
+		t.Errorf("Expected 4 runs, got %d", runs.Load())
+	}
+	if restarts.Load() != 3 {
+		t.Errorf("Expected 3 restart reports, got %d", restarts.Load())
+	}
+}
+
+func TestWorkerPanicContainment(t *testing.T) {
+	var stopped atomic.Bool
+
+	a, err := app.New(
+		app.WithSignals(),
+		func(rt *core.Runtime) error {
+			rt.Lifecycle.OnStop(func(ctx context.Context) error {
+				stopped.Store(true)
+				return nil
+			}, core.InStage(core.StageInfrastructure))
+			return nil
+		},
+		core.WithWorker(func(ctx context.Context) error {
+			panic("boom")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	a.Runtime().ErrorHandler = func(error) {}
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Wait() }()
+
+	select {
+	case err := <-done:
+		var panicErr *core.PanicError
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("Expected PanicError, got %v", err)
+		}
+		if panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+			t.Errorf("Unexpected panic report: %+v", panicErr)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("App should stop after worker panic")
+	}
+
+	if !stopped.Load() {
+		t.Error("Stop hooks should run after a worker panic")
+	}
+}
+
+func pollWorker(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestWorkerNames(t *testing.T) {
+	reports := make(chan core.ShutdownReport, 1)
+	a, err := app.New(
+		app.WithSignals(),
+		core.WithWorker(pollWorker),
+		core.WithWorker(pollWorker, core.WithHostedName("poller")),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	a.Runtime().ShutdownReporter = func(r core.ShutdownReport) { reports <- r }
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := a.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	var names []string
+	for _, h := range (<-reports).Hooks {
+		names = append(names, h.Name)
+	}
+	slices.Sort(names)
+	if got := strings.Join(names, ","); got != "poller,worker(tests.pollWorker)" {
+		t.Errorf("Unexpected worker hook names: %s", got)
+	}
+}
+
+func TestApplicationLifetime(t *testing.T) {
+	var lifetime core.ApplicationLifetime
+
+	a, err := app.New(
+		app.WithSignals(),
+		func(rt *core.Runtime) error {
+			rt.Lifecycle.OnStart(func(ctx context.Context) error {
+				return rt.Invoke(func(l core.ApplicationLifetime) {
+					lifetime = l
+				})
+			})
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	select {
+	case <-lifetime.Started():
+	default:
+		t.Fatal("Started should be closed after Start")
+	}
+	select {
+	case <-lifetime.Stopping():
+		t.Fatal("Stopping should not be closed while running")
+	default:
+	}
+
+	lifetime.StopApplication()
+
+	if err := a.Wait(); err != nil {
+		t.Errorf("Expected clean exit, got %v", err)
+	}
+	for name, ch := range map[string]<-chan struct{}{
+		"Stopping": lifetime.Stopping(),
+		"Stopped":  lifetime.Stopped(),
+	} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("%s should be closed after Wait", name)
+		}
+	}
+}
+
+func TestAppReload(t *testing.T) {
+	var order []string
+	var reported atomic.Int32
+
+	a, err := app.New(
+		app.WithSignals(),
+		app.WithReloadSignals(),
+		func(rt *core.Runtime) error {
+			rt.Lifecycle.OnReload(func(ctx context.Context) error {
+				order = append(order, "hosts")
+				return nil
+			}, core.InStage(core.StageHosts))
+			rt.Lifecycle.OnReload(func(ctx context.Context) error {
+				order = append(order, "services")
+				return errors.New("bad settings")
+			}, core.WithHookName("settings"), core.InStage(core.StageServices))
+			rt.Lifecycle.OnReload(func(ctx context.Context) error {
+				order = append(order, "infrastructure")
+				return nil
+			}, core.InStage(core.StageInfrastructure))
+			rt.ErrorHandler = func(err error) {
+				reported.Add(1)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// 启动前不执行重新加载钩子
+	if err := a.Runtime().Reload(); err != nil {
+		t.Fatalf("Reload before start failed: %v", err)
+	}
+	if len(order) != 0 {
+		t.Fatalf("Expected no reload hooks before start, got %v", order)
+	}
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	err = a.Runtime().Reload()
+	if err == nil || !strings.Contains(err.Error(), "settings") {
+		t.Errorf("Expected error naming the failed hook, got %v", err)
+	}
+	if got := strings.Join(order, ","); got != "infrastructure,services,hosts" {
+		t.Errorf("Expected reload in stage order, got %s", got)
+	}
+	if reported.Load() != 1 {
+		t.Errorf("Expected reload failure to be reported once, got %d", reported.Load())
+	}
+
+	// 重新加载失败不会导致应用退出
+	select {
+	case <-a.Runtime().Done():
+		t.Fatal("Reload failure should not stop the application")
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := a.Stop(ctx); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+}
+
+type requestScope struct{ id int }
+
+func TestBackgroundQueue(t *testing.T) {
+	reported := make(chan error, 4)
+	a, err := app.New(
+		app.WithSignals(),
+		core.WithBackgroundQueue(core.WithQueueWorkers(1), core.WithQueueCapacity(4)),
+		func(rt *core.Runtime) error {
+			rt.ErrorHandler = func(err error) { reported <- err }
+			return rt.Provide(func() *requestScope { return &requestScope{} }, di.WithScoped())
+		},
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	queue, err := di.Get[core.BackgroundQueue](a.Runtime().Container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	scopes := make(chan *requestScope, 2)
+	for range 2 {
+		err := queue.Enqueue(context.Background(), "send-email", func(ctx context.Context, scope di.Scope) error {
+			s, err := di.Get[*requestScope](scope)
+			scopes <- s
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	if first, second := <-scopes, <-scopes; first == second {
+		t.Error("Expected each task to run in its own scope")
+	}
+
+	// 不响应取消的任务会阻塞排空，直到停止超时
+	release := make(chan struct{})
+	defer close(release)
+	_ = queue.Enqueue(context.Background(), "stuck", func(ctx context.Context, scope di.Scope) error {
+		<-release
+		return nil
+	})
+	_ = queue.Enqueue(context.Background(), "queued", func(ctx context.Context, scope di.Scope) error {
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := a.Stop(ctx); err == nil {
+		t.Error("Expected stop to time out")
+	}
+	// 停止钩子超时后 Stop 即返回，放弃报告可能稍后才到达
+	timeout := time.After(time.Second)
+	for abandoned := false; !abandoned; {
+		select {
+		case err := <-reported:
+			abandoned = strings.Contains(err.Error(), "abandoned 2 task(s) [stuck, queued]")
+		case <-timeout:
+			t.Fatal("Expected abandoned tasks to be reported")
+		}
+	}
+
+	err = queue.Enqueue(context.Background(), "late", func(ctx context.Context, scope di.Scope) error { return nil })
+	if !errors.Is(err, core.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed after stop, got %v", err)
+	}
+}
+
+func TestAppCommands(t *testing.T) {
+	var out bytes.Buffer
+	var workerStarted atomic.Bool
+	var jobRuns atomic.Int32
+
+	var greeting string
+	a, err := app.New(
+		app.WithSignals(),
+		app.WithOutput(&out, &out),
+		app.WithCommand(app.Command{
+			Name:  "greet",
+			Usage: "<name>",
+			Short: "Print a greeting",
+			Flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&greeting, "greeting", "hello", "greeting word")
+			},
+			Run: func(ctx context.Context, inv *core.Invocation) error {
+				if len(inv.Args) != 1 {
+					return core.Usagef("greet: expected a name")
+				}
+				fmt.Fprintf(inv.Stdout, "%s %s\n", greeting, inv.Args[0])
+				return nil
+			},
+		}),
+		core.WithWorker(func(ctx context.Context) error {
+			workerStarted.Store(true)
+			<-ctx.Done()
+			return nil
+		}),
+		cron.New(cron.AddJob("@every 1h", "sync", func(env *core.Environment) error {
+			jobRuns.Add(1)
+			return nil
+		})),
+		logging.New(logging.WithConsole(logging.ConsoleLoggerOptions{Output: io.Discard})),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := a.Execute(ctx, []string{"--env", "Development", "greet", "--greeting", "hi", "gopher"}); err != nil {
+		t.Fatalf("greet failed: %v", err)
+	}
+	if out.String() != "hi gopher\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	if err := a.Execute(ctx, []string{"greet", "--env", "Development", "gopher"}); err != nil {
+		t.Fatalf("greet with --env after the command failed: %v", err)
+	}
+	if out.String() != "hello gopher\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	if err := a.Execute(ctx, []string{"cron", "run", "sync"}); err != nil {
+		t.Fatalf("cron run failed: %v", err)
+	}
+	if jobRuns.Load() != 1 {
+		t.Errorf("Expected job to run once, got %d", jobRuns.Load())
+	}
+
+	out.Reset()
+	if code := app.ExitCode(a.Execute(ctx, []string{"greet"})); code != 2 {
+		t.Errorf("Expected exit code 2 for usage error, got %d", code)
+	}
+	if !strings.Contains(out.String(), "Usage: greet <name>") {
+		t.Errorf("Expected usage error to print command help, got:\n%s", out.String())
+	}
+	out.Reset()
+	if code := app.ExitCode(a.Execute(ctx, []string{"unknown"})); code != 2 {
+		t.Errorf("Expected exit code 2 for unknown command, got %d", code)
+	}
+	if !strings.Contains(out.String(), "Commands:") {
+		t.Errorf("Expected unknown command to print the command list, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := app.ExitCode(a.Execute(ctx, []string{"help"})); code != 0 {
+		t.Errorf("Expected exit code 0 for help, got %d", code)
+	}
+	for _, name := range []string{"serve", "greet", "cron run", "di graph"} {
+		if !strings.Contains(out.String(), name) {
+			t.Errorf("Expected help to list %q, got:\n%s", name, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := a.Execute(ctx, []string{"di", "graph"}); err != nil {
+		t.Fatalf("di graph failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "core.ApplicationLifetime") {
+		t.Errorf("Expected di graph to list registered services, got:\n%s", out.String())
+	}
+
+	if workerStarted.Load() {
+		t.Error("Commands should not start hosted services")
+	}
+}
+
+type commandResource struct{ closed atomic.Bool }
+
+func (r *commandResource) Close() error {
+	r.closed.Store(true)
+	return nil
+}
+
+func TestCommandClosesContainer(t *testing.T) {
+	var resource *commandResource
+	a, err := app.New(
+		app.WithSignals(),
+		app.WithOutput(io.Discard, io.Discard),
+		func(rt *core.Runtime) error {
+			return rt.Provide(func() *commandResource { return &commandResource{} })
+		},
+		app.WithCommand(app.Command{
+			Name: "touch",
+			Run: func(ctx context.Context, inv *core.Invocation) error {
+				return inv.Runtime.Invoke(func(r *commandResource) { resource = r })
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if err := a.Execute(context.Background(), []string{"touch"}); err != nil {
+		t.Fatalf("touch failed: %v", err)
+	}
+	if resource == nil || !resource.closed.Load() {
+		t.Error("Expected the container to be closed after the command returns")
+	}
+}
+
+type failingService struct{}
+
+func TestNewClosesContainerOnBuildFailure(t *testing.T) {
+	var resource *commandResource
+	_, err := app.New(
+		app.WithSignals(),
+		app.WithOutput(io.Discard, io.Discard),
+		func(rt *core.Runtime) error {
+			if err := rt.Provide(func() *commandResource {
+				resource = &commandResource{}
+				return resource
+			}); err != nil {
+				return err
+			}
+			// 依赖 commandResource，保证构建失败时它已经创建
+			return rt.Provide(func(*commandResource) (*failingService, error) {
+				return nil, errors.New("boom")
+			})
+		},
+	)
+	if err == nil {
+		t.Fatal("Expected New to fail")
+	}
+	if resource == nil || !resource.closed.Load() {
+		t.Error("Expected singletons built before the failure to be closed")
+	}
+}
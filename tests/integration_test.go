@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/gocrud/app/config"
 	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
 	"github.com/gocrud/app/web"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -124,17 +126,10 @@ func TestIntegration(t *testing.T) {
 		t.Fatal("Web Host feature not found")
 	}
 
-	addr := ""
-	for i := 0; i < 20; i++ {
-		addr = host.Address()
-		if addr != "" && addr != ":0" {
-			break
-		}
-		time.Sleep(50 * time.Millisecond)
-	}
-
-	if addr == "" {
-		t.Fatal("Web Host address is empty after waiting")
+	// Start 会等待 Web Host 就绪，此时地址已确定
+	addr := host.Address()
+	if addr == "" || addr == ":0" {
+		t.Fatalf("Web Host address should be resolved after Start, got %q", addr)
 	}
 	t.Logf("Web Host running at %s", addr)
 
@@ -222,3 +217,168 @@ func TestHostedService(t *testing.T) {
 		t.Error("Worker should be stopped")
 	}
 }
+
+// NotReadyService 在就绪前退出的托管服务
+type NotReadyService struct {
+	ready   chan struct{}
+	stopped bool
+}
+
+func (s *NotReadyService) Start(ctx context.Context) error {
+	return fmt.Errorf("bind failed")
+}
+
+func (s *NotReadyService) Stop(ctx context.Context) error {
+	s.stopped = true
+	return nil
+}
+
+func (s *NotReadyService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func TestHostedServiceReadiness(t *testing.T) {
+	rt := core.NewRuntime()
+
+	svc := &NotReadyService{ready: make(chan struct{})}
+	if err := rt.Apply(core.WithHostedService(svc, core.WithReadyTimeout(time.Second))); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := rt.Container.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	err := rt.Lifecycle.Start(context.Background(), rt.Container)
+	if err == nil {
+		t.Fatal("Expected start error for service that failed before becoming ready")
+	}
+	if !svc.stopped {
+		t.Error("Service that failed to become ready should be stopped")
+	}
+}
+
+// SlowReadyService 启动一段时间后才就绪的托管服务
+type SlowReadyService struct {
+	ready chan struct{}
+}
+
+func (s *SlowReadyService) Start(ctx context.Context) error {
+	time.Sleep(20 * time.Millisecond)
+	close(s.ready)
+	<-ctx.Done()
+	return nil
+}
+
+func (s *SlowReadyService) Stop(ctx context.Context) error {
+	return nil
+}
+
+func (s *SlowReadyService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func TestHostedServiceZeroReadyTimeout(t *testing.T) {
+	rt := core.NewRuntime()
+
+	// 超时时间为 0 时使用默认值，而不是立即超时
+	svc := &SlowReadyService{ready: make(chan struct{})}
+	if err := rt.Apply(core.WithHostedService(svc, core.WithReadyTimeout(0))); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := rt.Container.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if err := rt.Lifecycle.Start(context.Background(), rt.Container); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := rt.Lifecycle.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
+
+func TestHostedServiceDiscovery(t *testing.T) {
+	rt := core.NewRuntime()
+
+	worker := &TestWorker{
+		Started: make(chan struct{}),
+		Stopped: make(chan struct{}),
+		StopCh:  make(chan struct{}),
+	}
+
+	// 模块只需注册服务，无需接触 rt.Lifecycle
+	if err := rt.Provide(worker, core.AsHosted()); err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+	if err := rt.Container.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := rt.Lifecycle.Start(ctx, rt.Container); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	select {
+	case <-worker.Started:
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Discovered worker should be started")
+	}
+
+	if err := rt.Lifecycle.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	select {
+	case <-worker.Stopped:
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Discovered worker should be stopped")
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	rt := core.NewRuntime()
+
+	err := rt.Apply(
+		web.New(web.WithPort(0), web.WithHealthChecks()),
+		func(rt *core.Runtime) error {
+			return health.RegistryFrom(rt).RegisterFunc("cache", func(ctx context.Context) error {
+				return fmt.Errorf("connection refused")
+			}, health.WithFailureStatus(health.StatusDegraded))
+		},
+	)
+	if err != nil {
+		t.Fatalf("Apply options failed: %v", err)
+	}
+	if err := rt.Container.Build(); err != nil {
+		t.Fatalf("Container build failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rt.Lifecycle.Start(ctx, rt.Container); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer rt.Lifecycle.Stop(ctx)
+
+	addr := core.GetFeature[*web.Host](rt).Address()
+
+	for path, want := range map[string]string{
+		"/healthz": `"status":"Healthy"`,
+		"/readyz":  `"status":"Degraded"`,
+	} {
+		resp, err := http.Get(fmt.Sprintf("http://%s%s", addr, path))
+		if err != nil {
+			t.Fatalf("HTTP Get %s failed: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", path, resp.StatusCode)
+		}
+		if !strings.Contains(string(body), want) {
+			t.Errorf("%s: expected body to contain %s, got %s", path, want, body)
+		}
+	}
+}
@@ -2,13 +2,19 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"reflect"
+	"sync"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gocrud/app/core"
 	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/health"
 	"github.com/gocrud/app/logging"
 )
 
@@ -19,6 +25,10 @@ type Builder struct {
 	engine          *gin.Engine
 	controllerCtors []any // 存储控制器构造函数或实例
 	registeredTypes []reflect.Type
+	healthChecks    bool
+	certFile        string
+	keyFile         string
+	systemdSocket   *string // 非 nil 时优先使用 socket 激活继承的监听器
 }
 
 // NewBuilder 创建 Web 构建器
@@ -51,6 +61,21 @@ func (b *Builder) UsePort(port int) *Builder {
 	return b
 }
 
+// UseTLS 启用 HTTPS，证书在启动时加载，重新加载 (SIGHUP) 时从相同路径重新读取
+func (b *Builder) UseTLS(certFile, keyFile string) *Builder {
+	b.certFile = certFile
+	b.keyFile = keyFile
+	return b
+}
+
+// UseSystemdSocket 使用 systemd socket 激活 (LISTEN_FDS) 继承的监听器
+// name 为单元中 FileDescriptorName 配置的名称，为空时使用第一个继承的监听器；
+// 未通过 socket 激活启动时仍监听 UsePort 指定的端口。
+func (b *Builder) UseSystemdSocket(name string) *Builder {
+	b.systemdSocket = &name
+	return b
+}
+
 // Use 使用全局中间件
 func (b *Builder) Use(middleware ...gin.HandlerFunc) *Builder {
 	b.engine.Use(middleware...)
@@ -162,6 +187,25 @@ func (b *Builder) SetMode(mode string) *Builder {
 	return b
 }
 
+// MapHealthChecks 将健康检查注册表映射为 /healthz 与 /readyz 路由
+func (b *Builder) MapHealthChecks(registry *health.Registry) *Builder {
+	b.engine.GET("/healthz", healthHandler(registry.Live))
+	b.engine.GET("/readyz", healthHandler(registry.Ready))
+	return b
+}
+
+// healthHandler 执行检查并输出 JSON 报告，不健康时返回 503
+func healthHandler(check func(context.Context) health.Report) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := check(c.Request.Context())
+		status := http.StatusOK
+		if !report.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
+
 // Engine 获取 Gin 引擎（用于高级定制）
 func (b *Builder) Engine() *gin.Engine {
 	return b.engine
@@ -195,17 +239,37 @@ func (b *Builder) RegisterServices(container di.Container) error {
 // Build 构建 Web 主机
 // 这里的 container 必须是全局的 DI 容器，用于后续解析 Controller
 func (b *Builder) Build(container di.Container) *Host {
-	return &Host{
+	host := &Host{
 		port:            b.port,
 		engine:          b.engine,
 		container:       container,
 		controllerTypes: b.registeredTypes,
-		server: &http.Server{
-			Addr:    fmt.Sprintf(":%d", b.port),
-			Handler: b.engine,
-		},
-		logger: b.logger,
+		logger:          b.logger,
+		ready:           make(chan struct{}),
+		certFile:        b.certFile,
+		keyFile:         b.keyFile,
+		systemdSocket:   b.systemdSocket,
 	}
+	host.server = host.newServer()
+	return host
+}
+
+// newServer 创建 HTTP 服务器
+// http.Server 在 Shutdown 之后不能再次使用，因此每次 Start 都会创建新的服务器。
+func (h *Host) newServer() *http.Server {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", h.port),
+		Handler: h.engine,
+	}
+	if h.certFile != "" {
+		// 每次握手读取当前证书，重新加载证书无需重启监听
+		server.TLSConfig = &tls.Config{
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return h.certificate.Load(), nil
+			},
+		}
+	}
+	return server
 }
 
 // inferServiceType 尝试推断服务类型（仅用于错误恢复）
@@ -227,47 +291,96 @@ func inferServiceType(target any) reflect.Type {
 type Host struct {
 	port            int
 	engine          *gin.Engine
-	server          *http.Server
+	serverMu        sync.Mutex
+	server          *http.Server // 当前 Start 使用的服务器，由 serverMu 保护
 	logger          logging.Logger
 	container       di.Container
 	controllerTypes []reflect.Type
+	ready           chan struct{}
+	readyOnce       sync.Once
+	routesOnce      sync.Once // 重启策略下 Start 可能被多次调用，路由只注册一次
+	routesErr       error
+	certFile        string
+	keyFile         string
+	certificate     atomic.Pointer[tls.Certificate]
+	systemdSocket   *string
+}
+
+// Ready 实现 core.ReadyNotifier
+// 控制器路由注册完成且端口开始监听后，返回的通道会被关闭。
+func (h *Host) Ready() <-chan struct{} {
+	return h.ready
 }
 
 // Address 获取监听地址 (e.g., "[::]:50234")
-// 仅在 Start 后有效
+// 仅在 Ready 之后有效
 func (h *Host) Address() string {
+	h.serverMu.Lock()
+	defer h.serverMu.Unlock()
 	if h.server != nil {
 		return h.server.Addr
 	}
 	return ""
 }
 
+// ReloadCertificate 从磁盘重新加载 TLS 证书
+// 加载失败时继续使用原有证书；未启用 TLS 时不做任何操作。
+func (h *Host) ReloadCertificate() error {
+	if h.certFile == "" {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(h.certFile, h.keyFile)
+	if err != nil {
+		return fmt.Errorf("web: failed to load TLS certificate: %w", err)
+	}
+	h.certificate.Store(&cert)
+	return nil
+}
+
 // Start 启动 Web 主机
 // 注意：此方法会阻塞，直到服务退出。框架会在独立的 Goroutine 中调用它。
+// 配置了重启策略时可以再次调用（包括 Stop 之后），每次调用都会重新监听并使用新的服务器，
+// 控制器路由只在第一次调用时注册。
 func (h *Host) Start(ctx context.Context) error {
 	// 1. 延迟解析并注册控制器路由
-	if err := h.mapControllers(); err != nil {
-		return fmt.Errorf("web: failed to map controllers: %w", err)
+	h.routesOnce.Do(func() { h.routesErr = h.mapControllers() })
+	if h.routesErr != nil {
+		return fmt.Errorf("web: failed to map controllers: %w", h.routesErr)
+	}
+
+	// 加载 TLS 证书，证书无效时不监听端口
+	if err := h.ReloadCertificate(); err != nil {
+		return err
 	}
 
 	// 2. 监听端口 (同步，确保端口可用)
-	addr := fmt.Sprintf(":%d", h.port)
-	ln, err := net.Listen("tcp", addr)
+	ln, err := h.listen()
 	if err != nil {
-		return fmt.Errorf("web: failed to listen on %s: %w", addr, err)
+		return err
 	}
 
-	// 更新 server 地址
-	h.server.Addr = ln.Addr().String()
+	// 使用新的服务器并更新地址
+	server := h.newServer()
+	server.Addr = ln.Addr().String()
+	h.serverMu.Lock()
+	h.server = server
+	h.serverMu.Unlock()
+
+	// 通知框架主机已就绪
+	h.readyOnce.Do(func() { close(h.ready) })
 
 	if h.logger != nil {
 		h.logger.Info("Web host started",
-			logging.Field{Key: "address", Value: h.server.Addr})
+			logging.Field{Key: "address", Value: server.Addr})
 	}
 
 	// 3. 启动服务 (阻塞)
 	// Serve 会一直阻塞直到 Shutdown 被调用或发生错误
-	if err := h.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+	serve := server.Serve
+	if h.certFile != "" {
+		serve = func(ln net.Listener) error { return server.ServeTLS(ln, "", "") }
+	}
+	if err := serve(ln); err != nil && err != http.ErrServerClosed {
 		if h.logger != nil {
 			h.logger.Error("Web host error", logging.Field{Key: "error", Value: err.Error()})
 		}
@@ -277,13 +390,39 @@ func (h *Host) Start(ctx context.Context) error {
 	return nil
 }
 
+// listen 返回继承的监听器，没有可用的继承监听器时监听配置的端口
+func (h *Host) listen() (net.Listener, error) {
+	if h.systemdSocket != nil {
+		// 继承的监听器同样会在优雅升级时传给新进程
+		ln, err := core.ListenSystemd(*h.systemdSocket)
+		if err == nil {
+			return ln, nil
+		}
+		if !errors.Is(err, core.ErrNotSocketActivated) {
+			return nil, fmt.Errorf("web: %w", err)
+		}
+	}
+
+	// 通过 core.Listen 监听，优雅升级时监听器会传给新进程
+	addr := fmt.Sprintf(":%d", h.port)
+	ln, err := core.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("web: failed to listen on %s: %w", addr, err)
+	}
+	return ln, nil
+}
+
 // Stop 停止 Web 主机
 func (h *Host) Stop(ctx context.Context) error {
 	if h.logger != nil {
 		h.logger.Info("Stopping web host")
 	}
 
-	if err := h.server.Shutdown(ctx); err != nil {
+	h.serverMu.Lock()
+	server := h.server
+	h.serverMu.Unlock()
+
+	if err := server.Shutdown(ctx); err != nil {
 		if h.logger != nil {
 			h.logger.Error("Failed to shutdown web host gracefully",
 				logging.Field{Key: "error", Value: err.Error()})
@@ -0,0 +1,238 @@
+package web_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/di"
+	"github.com/gocrud/app/web"
+)
+
+type pingController struct{}
+
+func (c *pingController) MountRoutes(router gin.IRouter) {
+	router.GET("/ping", func(ctx *gin.Context) { ctx.String(200, "pong") })
+}
+
+// startHost 在后台启动主机并等待就绪，返回 Start 的结果通道
+func startHost(t *testing.T, host *web.Host) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- host.Start(context.Background()) }()
+	select {
+	case <-host.Ready():
+	case err := <-done:
+		t.Fatalf("Start failed: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Host did not become ready")
+	}
+	return done
+}
+
+// getPing 请求主机的 /ping，主机尚未开始监听时重试
+// 重新启动时就绪通道已经关闭，无法用来等待监听。
+func getPing(t *testing.T, host *web.Host) string {
+	t.Helper()
+	client := &http.Client{Timeout: time.Second}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, port, _ := net.SplitHostPort(host.Address())
+		resp, err := client.Get("http://127.0.0.1:" + port + "/ping")
+		if err == nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return string(body)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET /ping failed: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHostRestart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := di.NewContainer()
+	builder := web.NewBuilder().UsePort(0).AddControllers(&pingController{})
+	if err := builder.RegisterServices(c); err != nil {
+		t.Fatalf("RegisterServices failed: %v", err)
+	}
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	host := builder.Build(c)
+
+	// 重启策略下 Start 会在 Stop 之后被再次调用，不能重复关闭就绪通道或重复注册路由，且需要继续提供服务
+	for i := range 2 {
+		done := startHost(t, host)
+		if body := getPing(t, host); body != "pong" {
+			t.Errorf("Start #%d: expected pong, got %q", i+1, body)
+		}
+		if err := host.Stop(context.Background()); err != nil {
+			t.Fatalf("Stop failed: %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("Start returned error: %v", err)
+		}
+	}
+}
+
+type childController struct{ served chan struct{} }
+
+func (c *childController) MountRoutes(router gin.IRouter) {
+	router.GET("/ping", func(ctx *gin.Context) {
+		ctx.String(200, "child")
+		close(c.served)
+	})
+}
+
+func TestHostRestartUpgrade(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 新进程：复用继承的监听器，处理一个请求后退出
+	if os.Getenv("RESTART_UPGRADE_TEST_CHILD") == "1" {
+		controller := &childController{served: make(chan struct{})}
+		c := di.NewContainer()
+		builder := web.NewBuilder().UsePort(0).AddControllers(controller)
+		if err := builder.RegisterServices(c); err != nil {
+			os.Exit(1)
+		}
+		if err := c.Build(); err != nil {
+			os.Exit(1)
+		}
+		host := builder.Build(c)
+		go func() { _ = host.Start(context.Background()) }()
+		select {
+		case <-host.Ready():
+		case <-time.After(5 * time.Second):
+			os.Exit(1)
+		}
+		_ = core.UpgradeReady()
+		select {
+		case <-controller.served:
+		case <-time.After(10 * time.Second):
+		}
+		_ = host.Stop(context.Background())
+		os.Exit(0)
+	}
+
+	c := di.NewContainer()
+	builder := web.NewBuilder().UsePort(0).AddControllers(&pingController{})
+	if err := builder.RegisterServices(c); err != nil {
+		t.Fatalf("RegisterServices failed: %v", err)
+	}
+	if err := c.Build(); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	host := builder.Build(c)
+
+	// 重启后监听器的序号需要与新进程第一次监听时一致
+	done := startHost(t, host)
+	if err := host.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	<-done
+	done = startHost(t, host)
+	if body := getPing(t, host); body != "pong" {
+		t.Fatalf("Expected pong after restart, got %q", body)
+	}
+
+	t.Setenv("RESTART_UPGRADE_TEST_CHILD", "1")
+	args := os.Args
+	os.Args = []string{args[0], "-test.run=^TestHostRestartUpgrade$"}
+	defer func() { os.Args = args }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := core.Upgrade(ctx); err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+
+	// 旧进程停止后，请求由新进程处理
+	if err := host.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	<-done
+	if body := getPing(t, host); body != "child" {
+		t.Errorf("Expected the new process to serve, got %q", body)
+	}
+}
+
+func TestHostSystemdSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 子进程：模拟 systemd socket 激活，文件描述符 3 为继承的监听器
+	if os.Getenv("SYSTEMD_SOCKET_TEST_CHILD") == "1" {
+		os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		c := di.NewContainer()
+		builder := web.NewBuilder().UsePort(1).UseSystemdSocket("http").AddControllers(&pingController{})
+		if err := builder.RegisterServices(c); err != nil {
+			os.Exit(1)
+		}
+		if err := c.Build(); err != nil {
+			os.Exit(1)
+		}
+		// 重启后继承的监听器需要仍然可用
+		host := builder.Build(c)
+		done := make(chan error, 1)
+		go func() { done <- host.Start(context.Background()) }()
+		select {
+		case <-host.Ready():
+		case <-time.After(5 * time.Second):
+			os.Exit(1)
+		}
+		if err := host.Stop(context.Background()); err != nil {
+			os.Exit(1)
+		}
+		if err := <-done; err != nil {
+			os.Exit(1)
+		}
+		if err := host.Start(context.Background()); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+	file, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("File failed: %v", err)
+	}
+	defer file.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHostSystemdSocket$")
+	cmd.Env = append(os.Environ(), "SYSTEMD_SOCKET_TEST_CHILD=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=http")
+	cmd.ExtraFiles = []*os.File{file}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start child: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// 父进程不接受连接，请求只能由子进程通过继承的监听器处理
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + ln.Addr().String() + "/ping")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "pong" {
+		t.Errorf("Expected pong from the inherited listener, got %q", body)
+	}
+}
@@ -1,9 +1,12 @@
 package web
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/gin-gonic/gin"
 	"github.com/gocrud/app/core"
+	"github.com/gocrud/app/health"
 )
 
 // BuilderOption 用于配置 Web Builder
@@ -16,6 +19,22 @@ func WithPort(port int) BuilderOption {
 	}
 }
 
+// WithTLS 启用 HTTPS
+// 重新加载 (SIGHUP 或 Runtime.Reload) 时会重新读取证书文件，已建立的连接不受影响。
+func WithTLS(certFile, keyFile string) BuilderOption {
+	return func(b *Builder) {
+		b.UseTLS(certFile, keyFile)
+	}
+}
+
+// WithSystemdSocket 使用 systemd socket 激活继承的监听器，name 为空时使用第一个
+// 未通过 socket 激活启动时仍监听 WithPort 指定的端口。
+func WithSystemdSocket(name string) BuilderOption {
+	return func(b *Builder) {
+		b.UseSystemdSocket(name)
+	}
+}
+
 // WithControllers 添加控制器
 func WithControllers(controllers ...any) BuilderOption {
 	return func(b *Builder) {
@@ -23,13 +42,27 @@ func WithControllers(controllers ...any) BuilderOption {
 	}
 }
 
+// WithHealthChecks 注册健康检查路由
+// GET /healthz 执行存活检查 (health.TagLive)，GET /readyz 执行就绪检查 (health.TagReady)。
+// 报告为 Unhealthy 时返回 503，否则返回 200，响应体为 JSON 格式的健康报告。
+func WithHealthChecks() BuilderOption {
+	return func(b *Builder) {
+		b.healthChecks = true
+	}
+}
+
 // New 启用 Web 能力
 func New(opts ...BuilderOption) core.Option {
-	return func(rt *core.Runtime) error {
+	return core.WithModule(core.NewModule("web", func(rt *core.Runtime) error {
 		// 1. 创建 WebBuilder
 		// TODO: 注入 Logger
 		builder := NewBuilder()
 
+		// 开发环境使用 Gin 调试模式，选项中的 SetMode 可以覆盖
+		if env := core.EnvironmentFrom(rt); env != nil && env.IsDevelopment() {
+			builder.SetMode(gin.DebugMode)
+		}
+
 		// 应用选项
 		for _, opt := range opts {
 			opt(builder)
@@ -38,11 +71,26 @@ func New(opts ...BuilderOption) core.Option {
 		// 2. 注册为 Feature
 		rt.Features.Set(builder)
 
+		if builder.healthChecks {
+			builder.MapHealthChecks(health.RegistryFrom(rt))
+		}
+
 		// 立即注册控制器服务到容器，因为容器很快就会被 Build
 		if err := builder.RegisterServices(rt.Container); err != nil {
 			return fmt.Errorf("web: failed to register services: %w", err)
 		}
 
+		// 重新加载时更新 TLS 证书
+		if builder.certFile != "" {
+			rt.Lifecycle.OnReload(func(ctx context.Context) error {
+				host := core.GetFeature[*Host](rt)
+				if host == nil {
+					return nil
+				}
+				return host.ReloadCertificate()
+			}, core.WithHookName("web"), core.InStage(core.StageHosts))
+		}
+
 		// 3. 注册 Host 为 HostedService
 		// 使用工厂函数延迟创建 Host，确保在 DI 容器构建后执行
 		hostFactory := func() *Host {
@@ -54,5 +102,5 @@ func New(opts ...BuilderOption) core.Option {
 
 		// 使用 core.WithHostedService 自动管理生命周期 (Start/Stop)
 		return core.WithHostedService(hostFactory)(rt)
-	}
+	}))
 }